@@ -1,74 +1,157 @@
 package analyzer
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/alex-slynko/haornot/types"
-	"k8s.io/api/apps/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/api/core/v1"
 )
 
-const notEnoughReplicasMessage = "At least 2 replicas required for deployment"
+const notEnoughReplicasMessage = "At least %d replicas required for %s"
 const readinessProbeMissingMessage = "Pod %s does not have readiness probe"
 const imageVersionMessage = "Image %s for pod %s does not have version. It will always use latest"
 
-var ErrNotADeployment = fmt.Errorf("Not a deployment")
+// ErrNotAWorkload is returned for manifests that are not Deployment, StatefulSet, DaemonSet, Job, CronJob,
+// or Pod and ReplicaSet without a controller
+var ErrNotAWorkload = fmt.Errorf("Not a workload")
+
+// Deprecated: use ErrNotAWorkload
+var ErrNotADeployment = ErrNotAWorkload
 
 func Analyze(yaml []byte) (*types.Message, error) {
-	deployment, err := parseDeployment(yaml)
+	return AnalyzeWithConfig(yaml, Config{})
+}
+
+func AnalyzeWithConfig(yaml []byte, config Config) (*types.Message, error) {
+	w, err := parseWorkload(yaml)
 
 	if err != nil {
 		return nil, err
 	}
 
-	msg := types.Message{Name: deployment.Name}
-
-	if deployment.Spec.Replicas == nil {
-		msg.Errors = []string{notEnoughReplicasMessage}
-		return &msg, nil
+	profile, err := config.profileFor(w.ObjectMeta)
+	if err != nil {
+		return nil, err
 	}
-	r := *deployment.Spec.Replicas
-	if r < 2 {
-		msg.Errors = []string{notEnoughReplicasMessage}
-		return &msg, nil
+	return analyzeWorkload(w, profile, config.CustomRules, policyFindings{}, related{}), nil
+}
+
+func analyzeWorkload(w *workload, profile Profile, customRules []CustomRule, findings policyFindings, input related) *types.Message {
+	msg := types.Message{Kind: w.Kind, Name: w.Name, Namespace: w.Namespace, Warnings: w.Warnings, Source: w.Source}
+	card := &scorecard{}
+	// finish adds custom rules and policy findings to errors of built-in rules
+	finish := func(errors []string) *types.Message {
+		customErrors, customWarnings := analyzeCustomRules(w, profile, customRules, card)
+		msg.Fixes = workloadFixes(w, profile, errors)
+		msg.Errors = append(errors, customErrors...)
+		msg.Warnings = append(msg.Warnings, customWarnings...)
+		findings.addTo(&msg, card)
+		msg.Score = card.score()
+		return &msg
 	}
 
-	errors := []string{}
-	for _, c := range deployment.Spec.Template.Spec.Containers {
-		if c.ReadinessProbe == nil {
-			errors = append(errors, fmt.Sprintf(readinessProbeMissingMessage, c.Name))
+	// Kubernetes runs 1 replica when replicas are not specified
+	replicas := int32(1)
+	if w.Replicas != nil {
+		replicas = *w.Replicas
+	}
+	if profile.enabled(ReplicasRule) && ruleApplies(ReplicasRule, w) {
+		errors := []string{}
+		if replicas < profile.minReplicas() {
+			errors = append(errors, fmt.Sprintf(notEnoughReplicasMessage, profile.minReplicas(), strings.ToLower(w.Kind)))
 		}
-
-		if !strings.Contains(c.Image, ":") {
-			errors = append(errors, fmt.Sprintf(imageVersionMessage, c.Image, c.Name))
+		if len(card.check(ruleSeverities[ReplicasRule], errors)) > 0 {
+			return finish(errors)
 		}
 	}
-	msg.Errors = errors
 
-	return &msg, nil
+	errors := []string{}
+	spec := w.Template.Spec
+	checks := []struct {
+		rule  string
+		check func() []string
+	}{
+		{StrategyRule, func() []string { return analyzeStrategy(w.Deployment.Spec.Strategy, replicas) }},
+		{ReadinessProbeRule, func() []string { return analyzeReadinessProbes(spec) }},
+		{ProbesRule, func() []string {
+			errors := []string{}
+			for _, c := range spec.Containers {
+				errors = append(errors, analyzeProbes(c, w.newer.StartupProbes[c.Name])...)
+			}
+			return errors
+		}},
+		{ImageVersionRule, func() []string { return analyzeImageVersions(spec) }},
+		{ResourcesRule, func() []string { return analyzeResources(spec, true, false) }},
+		{GuaranteedQoSRule, func() []string { return analyzeResources(spec, false, true) }},
+		{GracefulShutdownRule, func() []string { return analyzeShutdown(spec) }},
+		{ZoneSpreadRule, func() []string { return analyzeZoneSpread(spec, w.newer.TopologySpreadKeys) }},
+		{StorageRule, func() []string { return analyzeStorage(w, replicas, input.claims, profile.persistentPaths()) }},
+		{NodePinningRule, func() []string { return analyzeNodePinning(spec) }},
+		{HostPortsRule, func() []string { return analyzeHostPorts(w, replicas) }},
+		{PriorityClassRule, func() []string {
+			return analyzePriorityClass(w, input.priorityClasses, profile.PriorityClassSelector, profile.MinPriority)
+		}},
+		{RolloutRule, func() []string { return analyzeRollout(w.Deployment.Spec, profile) }},
+		{JobRule, func() []string { return analyzeJob(*w.Job) }},
+		{CronJobRule, func() []string { return analyzeCronJob(*w.CronJob) }},
+		{ControllerRule, func() []string { return analyzeController(w) }},
+	}
+	for _, c := range checks {
+		if !profile.enabled(c.rule) || !ruleApplies(c.rule, w) {
+			continue
+		}
+		errors = append(errors, card.check(ruleSeverities[c.rule], c.check())...)
+	}
+	return finish(errors)
 }
 
-func parseDeployment(yaml []byte) (*v1.Deployment, error) {
-	decode := scheme.Codecs.UniversalDeserializer().Decode
-
-	gvk := &schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
-	obj, meta, err := decode([]byte(yaml), gvk, nil)
-	if err != nil {
-		return &v1.Deployment{}, err
+// ruleApplies is false for rules that do not make sense for the kind of workload
+func ruleApplies(rule string, w *workload) bool {
+	switch rule {
+	case JobRule:
+		return w.Job != nil
+	case CronJobRule:
+		return w.CronJob != nil
+	case ControllerRule:
+		return bare(w)
+	}
+	if w.Job != nil {
+		// Pods of Jobs run to completion, so rules for long running pods do not apply
+		return rule == ImageVersionRule
 	}
+	if bare(w) {
+		return containerRules[rule]
+	}
+	switch rule {
+	case StrategyRule, RolloutRule:
+		return w.Deployment != nil
+	case ReplicasRule:
+		// DaemonSet runs a pod on every node, so replicas are not checked
+		return w.Kind != "DaemonSet"
+	case ZoneSpreadRule, NodePinningRule, HostPortsRule:
+		// DaemonSet runs a pod on every node it selects, so it is spread across nodes and zones
+		return w.Kind != "DaemonSet"
+	}
+	return true
+}
 
-	if meta.Kind == "Deployment" {
-		b, err := json.Marshal(obj)
-		if err != nil {
-			return &v1.Deployment{}, err
+func analyzeReadinessProbes(spec corev1.PodSpec) []string {
+	errors := []string{}
+	for _, c := range spec.Containers {
+		if c.ReadinessProbe == nil {
+			errors = append(errors, fmt.Sprintf(readinessProbeMissingMessage, c.Name))
 		}
-		deployment := &v1.Deployment{}
-		err = json.Unmarshal(b, deployment)
-		return deployment, err
 	}
-	return &v1.Deployment{}, ErrNotADeployment
+	return errors
+}
 
+func analyzeImageVersions(spec corev1.PodSpec) []string {
+	errors := []string{}
+	for _, c := range spec.Containers {
+		if !strings.Contains(c.Image, ":") {
+			errors = append(errors, fmt.Sprintf(imageVersionMessage, c.Image, c.Name))
+		}
+	}
+	return errors
 }
@@ -1,50 +1,11 @@
 package analyzer_test
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/alex-slynko/haornot/analyzer"
-	msg "github.com/alex-slynko/haornot/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/types"
 )
 
-func HaveMatchingElement(expected interface{}) types.GomegaMatcher {
-	return &matchingElementMatcher{
-		expected: expected,
-	}
-}
-
-type matchingElementMatcher struct {
-	expected interface{}
-}
-
-func (matcher *matchingElementMatcher) Match(actual interface{}) (success bool, err error) {
-	if actual == nil {
-		return false, nil
-	}
-	array := actual.(*msg.Message).Errors
-
-	substring := matcher.expected.(string)
-	for _, element := range array {
-		if strings.Contains(element, substring) {
-			return true, nil
-		}
-	}
-
-	return false, nil
-}
-
-func (matcher *matchingElementMatcher) FailureMessage(actual interface{}) (message string) {
-	return fmt.Sprintf("Expected\n\t%v\nto contain the element that matches\n\t%s", actual, matcher.expected)
-}
-
-func (matcher *matchingElementMatcher) NegatedFailureMessage(actual interface{}) (message string) {
-	return fmt.Sprintf("Expected\n\t%v\nnot to contain the element that matches\n\t%s", actual, matcher.expected)
-}
-
 var _ = Describe("Analyze", func() {
 	Context("when spec is invalid", func() {
 		It("returns error", func() {
@@ -0,0 +1,194 @@
+package analyzer
+
+import (
+	"github.com/alex-slynko/haornot/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Options configure AnalyzeObjects
+type Options struct {
+	// Config has rules and profiles, same as config file
+	Config Config
+	// Profile is used for objects that do not select profile with label or namespace
+	Profile string
+	// Rules enable or disable rules for all objects, on top of the selected profile
+	Rules map[string]bool
+	// Policies are evaluated for every object in addition to the rules
+	Policies *Policies
+	// Workers is the number of objects analyzed in parallel. Default is the number of CPUs
+	Workers int
+}
+
+// Result has messages in the same order as objects were passed
+type Result struct {
+	// Workloads has a message for every Deployment, StatefulSet, DaemonSet, Job, CronJob, and Pod and ReplicaSet without a controller
+	Workloads []*types.Message
+	// Services has a message for every Service
+	Services []*types.Message
+	// Others has messages for other objects that are denied or warned by policies
+	Others []*types.Message
+	// Failures has errors for objects that could not be analyzed. They are *ObjectError
+	Failures []error
+}
+
+// ObjectError is an error of one object. Kind, Namespace, Name and Source identify the object in the input
+type ObjectError struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Source    string
+	Err       error
+}
+
+func (e *ObjectError) Error() string {
+	return e.Err.Error()
+}
+
+func newObjectError(obj runtime.Object, err error) *ObjectError {
+	e := &ObjectError{Kind: obj.GetObjectKind().GroupVersionKind().Kind, Err: err}
+	if accessor, accessorErr := meta.Accessor(obj); accessorErr == nil {
+		e.Namespace = accessor.GetNamespace()
+		e.Name = accessor.GetName()
+		e.Source = accessor.GetAnnotations()[SourceAnnotation]
+	}
+	return e
+}
+
+// HasErrors is true when any object failed checks or could not be analyzed
+func (r *Result) HasErrors() bool {
+	if len(r.Failures) > 0 {
+		return true
+	}
+	for _, messages := range [][]*types.Message{r.Workloads, r.Services, r.Others} {
+		for _, msg := range messages {
+			if len(msg.Errors) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Score is the average score of all messages from 0 to 100. Objects that could not be analyzed score 0
+func (r *Result) Score() int {
+	total := 0
+	count := len(r.Failures)
+	for _, messages := range [][]*types.Message{r.Workloads, r.Services, r.Others} {
+		for _, msg := range messages {
+			total += msg.Score
+			count++
+		}
+	}
+	if count == 0 {
+		return 100
+	}
+	return total / count
+}
+
+// AnalyzeObjects analyzes typed or unstructured objects, for example from client-go or Decode.
+// Objects that are neither workloads nor services are only checked by policies.
+func AnalyzeObjects(objects []runtime.Object, options Options) (*Result, error) {
+	config := options.Config
+	if options.Profile != "" {
+		config.DefaultProfile = options.Profile
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	rules, err := config.knownRules()
+	if err != nil {
+		return nil, err
+	}
+	overrides := Profile{Rules: options.Rules}
+	if err := overrides.validate(rules); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	decoded := []*object{}
+	objectErrors := make([]error, len(objects))
+	all := make([]*object, len(objects))
+	parallel(len(objects), options.Workers, func(i int) {
+		all[i], objectErrors[i] = newObject(objects[i])
+	})
+	for i, err := range objectErrors {
+		if err != nil {
+			result.Failures = append(result.Failures, newObjectError(objects[i], err))
+			continue
+		}
+		decoded = append(decoded, all[i])
+	}
+
+	findings := make([]policyFindings, len(decoded))
+	if options.Policies != nil {
+		findings, err = options.Policies.evaluate(decoded, options.Workers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	input := related{claims: newClaimIndex(decoded), priorityClasses: newPriorityClassIndex(decoded)}
+	outcomes := make([]analysis, len(decoded))
+	parallel(len(decoded), options.Workers, func(i int) {
+		outcomes[i] = analyzeObject(decoded[i], config, overrides, findings[i], input)
+	})
+
+	workloads := []*workload{}
+	for i, outcome := range outcomes {
+		if outcome.workload != nil {
+			workloads = append(workloads, outcome.workload)
+		}
+		if outcome.err != nil {
+			o := decoded[i]
+			result.Failures = append(result.Failures, &ObjectError{Kind: o.gvk.Kind, Namespace: o.namespace(), Name: o.name(), Source: o.source, Err: outcome.err})
+		}
+		if outcome.workloadMessage != nil {
+			result.Workloads = append(result.Workloads, outcome.workloadMessage)
+		}
+		if outcome.otherMessage != nil {
+			result.Others = append(result.Others, outcome.otherMessage)
+		}
+	}
+
+	result.Services = analyzeServices(decoded, workloads, findings)
+	return result, nil
+}
+
+// related are objects from the same input that workloads refer to
+type related struct {
+	claims          claimIndex
+	priorityClasses priorityClassIndex
+}
+
+// analysis is the result of analyzing one object
+type analysis struct {
+	workload        *workload
+	workloadMessage *types.Message
+	otherMessage    *types.Message
+	err             error
+}
+
+func analyzeObject(o *object, config Config, overrides Profile, findings policyFindings, input related) analysis {
+	w, err := newWorkload(o)
+	if err == ErrNotAWorkload {
+		if _, ok := o.Object.(*corev1.Service); !ok && len(findings.deny)+len(findings.warn) > 0 {
+			msg := &types.Message{Kind: o.gvk.Kind, Name: o.name(), Namespace: o.namespace(), Source: o.source, Errors: []string{}}
+			card := &scorecard{}
+			findings.addTo(msg, card)
+			msg.Score = card.score()
+			return analysis{otherMessage: msg}
+		}
+		return analysis{}
+	}
+	if err != nil {
+		return analysis{err: err}
+	}
+
+	profile, err := config.profileFor(w.ObjectMeta)
+	if err != nil {
+		return analysis{workload: w, err: err}
+	}
+	return analysis{workload: w, workloadMessage: analyzeWorkload(w, profile.merge(overrides), config.CustomRules, findings, input)}
+}
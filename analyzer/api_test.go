@@ -0,0 +1,131 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("AnalyzeObjects", func() {
+	var deployment *appsv1.Deployment
+
+	BeforeEach(func() {
+		replicas := int32(1)
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "web"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.15"}},
+					},
+				},
+			},
+		}
+	})
+
+	It("analyzes typed objects", func() {
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.HasErrors()).To(BeTrue())
+		Expect(result.Workloads).To(HaveLen(1))
+		Expect(result.Workloads[0].Kind).To(Equal("Deployment"))
+		Expect(result.Workloads[0].Namespace).To(Equal("web"))
+		Expect(result.Workloads[0]).To(HaveMatchingElement("At least 2 replicas required for deployment"))
+	})
+
+	It("analyzes unstructured objects", func() {
+		obj, err := analyzer.Decode([]byte(`# Source: nginx/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+        startupProbe:
+          tcpSocket:
+            port: 80
+        livenessProbe:
+          initialDelaySeconds: 60
+          tcpSocket:
+            port: 80
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{obj}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads).To(HaveLen(1))
+		Expect(result.Workloads[0].Source).To(Equal("nginx/templates/deployment.yaml"))
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("startup probe"))
+	})
+
+	It("skips objects that are not workloads", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}}
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{configMap}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads).To(BeEmpty())
+		Expect(result.HasErrors()).To(BeFalse())
+	})
+
+	It("correlates services with workloads", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "web"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "nginx"},
+				Ports:    []corev1.ServicePort{{Port: 80}},
+			},
+		}
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{deployment, service}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Services).To(HaveLen(1))
+		Expect(result.Services[0]).To(HaveMatchingElement("does not have readiness probe"))
+	})
+
+	It("enables and disables rules", func() {
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{
+			Rules: map[string]bool{analyzer.ReplicasRule: false, analyzer.ZoneSpreadRule: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("replicas required"))
+		Expect(result.Workloads[0]).To(HaveMatchingElement("not spread across zones"))
+	})
+
+	It("uses profile from options", func() {
+		config := analyzer.Config{Profiles: map[string]analyzer.Profile{"dev": {MinReplicas: 1}}}
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{Config: config, Profile: "dev"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("replicas required"))
+	})
+
+	It("returns error for unknown profile", func() {
+		_, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{Profile: "dev"})
+		Expect(err).To(MatchError("unknown profile dev"))
+	})
+
+	It("returns error for unknown rule", func() {
+		_, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{Rules: map[string]bool{"replica": false}})
+		Expect(err).To(MatchError("unknown rule replica"))
+	})
+
+	It("reports objects that can not be analyzed", func() {
+		obj := decode("apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: gear\n")
+
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{obj}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Failures).To(HaveLen(1))
+		Expect(result.Failures[0]).To(BeAssignableToTypeOf(&analyzer.ObjectError{}))
+		Expect(result.Failures[0].(*analyzer.ObjectError).Kind).To(Equal("Widget"))
+		Expect(result.Failures[0].(*analyzer.ObjectError).Name).To(Equal("gear"))
+		Expect(result.HasErrors()).To(BeTrue())
+	})
+})
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const barePodMessage = "Pod is not managed by a controller. It is not recreated when it fails or its node is drained. Use %s instead"
+const bareReplicaSetMessage = "ReplicaSet is not owned by a Deployment. Template changes are not rolled out to running pods. Use Deployment instead"
+
+// containerRules apply to bare Pods and ReplicaSets. Other rules depend on the controller
+var containerRules = map[string]bool{
+	ReadinessProbeRule:   true,
+	ProbesRule:           true,
+	ImageVersionRule:     true,
+	ResourcesRule:        true,
+	GuaranteedQoSRule:    true,
+	GracefulShutdownRule: true,
+}
+
+// newPodWorkload converts Pod to workload. Pods created by controllers are not workloads, because their controller is analyzed
+func newPodWorkload(o *object, pod *corev1.Pod) (*workload, error) {
+	if managed(pod.ObjectMeta) {
+		return nil, ErrNotAWorkload
+	}
+	return &workload{
+		ObjectMeta: pod.ObjectMeta,
+		Kind:       "Pod",
+		Template:   corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec},
+		Source:     o.source,
+		newer:      o.newer,
+		fields:     o.fields,
+	}, nil
+}
+
+// managed is true for objects that have a controller, e.g. ReplicaSets created by Deployments
+func managed(meta metav1.ObjectMeta) bool {
+	return metav1.GetControllerOf(&meta) != nil
+}
+
+// bare is true for Pods and ReplicaSets that are not managed by a controller
+func bare(w *workload) bool {
+	return w.Kind == "Pod" || w.Kind == "ReplicaSet"
+}
+
+// analyzeController recommends the controller for bare Pods and ReplicaSets
+func analyzeController(w *workload) []string {
+	if w.Kind == "ReplicaSet" {
+		return []string{bareReplicaSetMessage}
+	}
+	controller := "Deployment"
+	if policy := w.Template.Spec.RestartPolicy; policy == corev1.RestartPolicyOnFailure || policy == corev1.RestartPolicyNever {
+		// Pods that are not restarted run to completion
+		controller = "Job"
+	}
+	return []string{fmt.Sprintf(barePodMessage, controller)}
+}
@@ -0,0 +1,102 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze bare pods and replicasets", func() {
+	pod := func(restartPolicy string) []byte {
+		return []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  restartPolicy: ` + restartPolicy + `
+  terminationGracePeriodSeconds: 0
+  containers:
+  - name: nginx
+    image: nginx
+`)
+	}
+
+	It("recommends Deployment for bare Pod and applies container checks", func() {
+		output, err := analyzer.Analyze(pod("Always"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("Pod"))
+		Expect(output).To(HaveMatchingElement("Pod is not managed by a controller. It is not recreated when it fails or its node is drained. Use Deployment instead"))
+		Expect(output).To(HaveMatchingElement("Pod nginx does not have readiness probe"))
+		Expect(output).To(HaveMatchingElement("Image nginx for pod nginx does not have version"))
+		Expect(output).NotTo(HaveMatchingElement("replicas required"))
+		Expect(output.Fixes).To(ConsistOf(types.Fix{
+			Finding: "terminationGracePeriodSeconds is 0. Pods are killed without graceful shutdown",
+			Path:    []string{"spec", "terminationGracePeriodSeconds"},
+			Value:   "30",
+		}))
+	})
+
+	It("reads startup probes of bare Pod containers", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.15
+    livenessProbe:
+      httpGet:
+        path: /
+        port: 80
+      initialDelaySeconds: 60
+    startupProbe:
+      httpGet:
+        path: /
+        port: 80
+      failureThreshold: 30
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pod is not managed by a controller"))
+		Expect(output).NotTo(HaveMatchingElement("startup probe"))
+	})
+
+	It("recommends Job for Pods that run to completion", func() {
+		output, err := analyzer.Analyze(pod("Never"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Use Job instead"))
+	})
+
+	It("recommends Deployment for standalone ReplicaSet", func() {
+		output, err := analyzer.Analyze(nginxManifest{kind: "ReplicaSet", replicas: "1"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("ReplicaSet"))
+		Expect(output).To(HaveMatchingElement("ReplicaSet is not owned by a Deployment. Template changes are not rolled out to running pods. Use Deployment instead"))
+		Expect(output).NotTo(HaveMatchingElement("replicas required"))
+	})
+
+	It("converts legacy ReplicaSets", func() {
+		output, err := analyzer.Analyze(nginxManifest{apiVersion: "extensions/v1beta1", kind: "ReplicaSet", replicas: "1"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("ReplicaSet is not owned by a Deployment"))
+		Expect(output.Warnings).To(ContainElement("extensions/v1beta1 ReplicaSet was removed in Kubernetes 1.16. Use apps/v1 instead"))
+	})
+
+	It("skips ReplicaSets and Pods that have a controller", func() {
+		_, err := analyzer.Analyze(nginxManifest{kind: "ReplicaSet", replicas: "1", metadata: `  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: nginx
+    uid: 6d2ea4b6-7c1f-11e8-9c2d-fa7ae01bbebc
+    controller: true`}.yaml())
+		Expect(err).To(Equal(analyzer.ErrNotAWorkload))
+	})
+
+	It("can disable controller rule in config", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{Rules: map[string]bool{analyzer.ControllerRule: false}}}
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{kind: "ReplicaSet", replicas: "1"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("ReplicaSet is not owned"))
+	})
+})
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const concurrentRunsMessage = "CronJob concurrencyPolicy is Allow. Runs that take longer than the schedule overlap"
+const startingDeadlineMissingMessage = "CronJob does not have startingDeadlineSeconds. It stops scheduling after 100 missed runs, for example when controller was down"
+const startingDeadlineTooShortMessage = "startingDeadlineSeconds is %d. Controller checks schedules every %d seconds, so runs can be missed"
+const noRetriesMessage = "backoffLimit is 0. Job fails on the first pod failure"
+const activeDeadlineMissingMessage = "Job does not have activeDeadlineSeconds. Hanging pods run forever"
+const jobRestartPolicyMissingMessage = "Job pods do not have restartPolicy. Use OnFailure or Never"
+const jobRestartPolicyMessage = "restartPolicy %s is not allowed for Job pods. Use OnFailure or Never"
+const removedCronJobVersionMessage = "%s CronJob was removed in Kubernetes 1.25. Use batch/v1 instead"
+
+// cronJobSyncSeconds is how often CronJob controller checks schedules
+const cronJobSyncSeconds = 10
+
+// olderVersions are API versions that the vendored API types do not have, with older versions of the same schema
+var olderVersions = map[schema.GroupVersionKind]schema.GroupVersion{
+	{Group: "batch", Version: "v1", Kind: "CronJob"}: batchv1beta1.SchemeGroupVersion,
+}
+
+// newBatchWorkload converts Job or CronJob to workload. It returns false for other objects
+func newBatchWorkload(o *object) (*workload, bool) {
+	w := &workload{Source: o.source, newer: o.newer, fields: o.fields}
+	switch t := o.Object.(type) {
+	case *batchv1.Job:
+		w.Kind = "Job"
+		w.ObjectMeta = t.ObjectMeta
+		w.Template = t.Spec.Template
+		w.Job = &t.Spec
+	case *batchv1beta1.CronJob:
+		w.Kind = "CronJob"
+		w.ObjectMeta = t.ObjectMeta
+		w.Template = t.Spec.JobTemplate.Spec.Template
+		w.Job = &t.Spec.JobTemplate.Spec
+		w.CronJob = &t.Spec
+		if o.gvk.GroupVersion() == batchv1beta1.SchemeGroupVersion {
+			w.Warnings = []string{fmt.Sprintf(removedCronJobVersionMessage, o.gvk.GroupVersion().String())}
+		}
+	default:
+		return nil, false
+	}
+	return w, true
+}
+
+// analyzeJob checks that failed and hanging pods of a Job are handled
+func analyzeJob(spec batchv1.JobSpec) []string {
+	errors := []string{}
+	if spec.BackoffLimit != nil && *spec.BackoffLimit == 0 {
+		errors = append(errors, noRetriesMessage)
+	}
+	if spec.ActiveDeadlineSeconds == nil {
+		errors = append(errors, activeDeadlineMissingMessage)
+	}
+	switch policy := spec.Template.Spec.RestartPolicy; policy {
+	case corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+	case "":
+		errors = append(errors, jobRestartPolicyMissingMessage)
+	default:
+		errors = append(errors, fmt.Sprintf(jobRestartPolicyMessage, policy))
+	}
+	return errors
+}
+
+// analyzeCronJob checks that runs do not overlap and are not lost
+func analyzeCronJob(spec batchv1beta1.CronJobSpec) []string {
+	errors := []string{}
+	if spec.ConcurrencyPolicy == "" || spec.ConcurrencyPolicy == batchv1beta1.AllowConcurrent {
+		errors = append(errors, concurrentRunsMessage)
+	}
+	switch {
+	case spec.StartingDeadlineSeconds == nil:
+		errors = append(errors, startingDeadlineMissingMessage)
+	case *spec.StartingDeadlineSeconds < cronJobSyncSeconds:
+		errors = append(errors, fmt.Sprintf(startingDeadlineTooShortMessage, *spec.StartingDeadlineSeconds, cronJobSyncSeconds))
+	}
+	return errors
+}
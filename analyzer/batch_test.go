@@ -0,0 +1,118 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze batch workloads", func() {
+	job := func(fields, restartPolicy string) []byte {
+		return []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+` + fields + `
+  template:
+    spec:
+      restartPolicy: ` + restartPolicy + `
+      containers:
+      - name: migrate
+        image: migrate:1.0
+`)
+	}
+	cronJob := func(apiVersion, fields string) []byte {
+		return []byte(`apiVersion: ` + apiVersion + `
+kind: CronJob
+metadata:
+  name: report
+spec:
+  schedule: "*/5 * * * *"
+` + fields + `
+  jobTemplate:
+    spec:
+      activeDeadlineSeconds: 600
+      template:
+        spec:
+          restartPolicy: Always
+          containers:
+          - name: report
+            image: report
+`)
+	}
+
+	It("is successful for Job with deadline and restart policy", func() {
+		output, err := analyzer.Analyze(job("  activeDeadlineSeconds: 600", "OnFailure"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("Job"))
+		Expect(output.Errors).To(BeEmpty())
+		Expect(output.Score).To(Equal(100))
+	})
+
+	It("does not check replicas and probes of Jobs", func() {
+		output, err := analyzer.Analyze(job("", "Never"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("replicas"))
+		Expect(output).NotTo(HaveMatchingElement("readiness probe"))
+		Expect(output).NotTo(HaveMatchingElement("resources"))
+	})
+
+	It("returns messages for Job without retries, deadline and restart policy", func() {
+		output, err := analyzer.Analyze(job("  backoffLimit: 0", "Always"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("backoffLimit is 0. Job fails on the first pod failure"))
+		Expect(output).To(HaveMatchingElement("Job does not have activeDeadlineSeconds. Hanging pods run forever"))
+		Expect(output).To(HaveMatchingElement("restartPolicy Always is not allowed for Job pods. Use OnFailure or Never"))
+
+		output, err = analyzer.Analyze(job("", `""`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Job pods do not have restartPolicy"))
+	})
+
+	It("returns messages for CronJob that allows concurrent runs and can miss them", func() {
+		output, err := analyzer.Analyze(cronJob("batch/v1", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("CronJob"))
+		Expect(output.Warnings).To(BeEmpty())
+		Expect(output).To(HaveMatchingElement("CronJob concurrencyPolicy is Allow. Runs that take longer than the schedule overlap"))
+		Expect(output).To(HaveMatchingElement("CronJob does not have startingDeadlineSeconds"))
+		Expect(output).To(HaveMatchingElement("restartPolicy Always is not allowed for Job pods"))
+		Expect(output).To(HaveMatchingElement("Image report for pod report does not have version"))
+		Expect(output).NotTo(HaveMatchingElement("activeDeadlineSeconds"))
+	})
+
+	It("accepts CronJob with concurrency policy and deadline", func() {
+		output, err := analyzer.Analyze(cronJob("batch/v1", "  concurrencyPolicy: Forbid\n  startingDeadlineSeconds: 300"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("CronJob"))
+
+		output, err = analyzer.Analyze(cronJob("batch/v1", "  concurrencyPolicy: Replace\n  startingDeadlineSeconds: 5"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("concurrencyPolicy"))
+		Expect(output).To(HaveMatchingElement("startingDeadlineSeconds is 5. Controller checks schedules every 10 seconds, so runs can be missed"))
+	})
+
+	It("warns about removed CronJob API version", func() {
+		output, err := analyzer.Analyze(cronJob("batch/v1beta1", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Warnings).To(ContainElement("batch/v1beta1 CronJob was removed in Kubernetes 1.25. Use batch/v1 instead"))
+	})
+
+	It("fixes concurrency policy and restart policy in the job template", func() {
+		output, err := analyzer.Analyze(cronJob("batch/v1", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Fixes).To(ConsistOf(
+			types.Fix{Finding: "CronJob concurrencyPolicy is Allow. Runs that take longer than the schedule overlap", Path: []string{"spec", "concurrencyPolicy"}, Value: "Forbid"},
+			types.Fix{Finding: "restartPolicy Always is not allowed for Job pods. Use OnFailure or Never", Path: []string{"spec", "jobTemplate", "spec", "template", "spec", "restartPolicy"}, Value: "OnFailure"},
+		))
+	})
+
+	It("can disable batch rules in config", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{Rules: map[string]bool{analyzer.JobRule: false, analyzer.CronJobRule: false}}}
+		output, err := analyzer.AnalyzeWithConfig(cronJob("batch/v1", ""), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(ConsistOf(ContainSubstring("does not have version")))
+	})
+})
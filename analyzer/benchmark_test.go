@@ -0,0 +1,109 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const benchmarkDeployment = `# Source: platform/templates/%s.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: %s
+  template:
+    metadata:
+      labels:
+        app: %s
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+        ports:
+        - containerPort: 80
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+        livenessProbe:
+          periodSeconds: 20
+          httpGet:
+            path: /
+            port: 80
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 64Mi
+`
+
+const benchmarkService = `apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+spec:
+  selector:
+    app: %s
+  ports:
+  - port: 80
+`
+
+// benchmarkBundle is a rendered bundle with a deployment and a service per application
+func benchmarkBundle(applications int) []byte {
+	documents := [][]byte{}
+	for i := 0; i < applications; i++ {
+		name := fmt.Sprintf("app-%d", i)
+		documents = append(documents, []byte(fmt.Sprintf(benchmarkDeployment, name, name, name, name)))
+		documents = append(documents, []byte(fmt.Sprintf(benchmarkService, name, name)))
+	}
+	return bytes.Join(documents, []byte("\n---\n"))
+}
+
+func benchmarkAnalyze(b *testing.B, workers int) {
+	manifests := bytes.Split(benchmarkBundle(2500), []byte("\n---"))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		decoded, errors := analyzer.DecodeManifests(manifests, workers)
+		objects := []runtime.Object{}
+		for i := range decoded {
+			if errors[i] != nil {
+				b.Fatal(errors[i])
+			}
+			objects = append(objects, decoded[i])
+		}
+		if _, err := analyzer.AnalyzeObjects(objects, analyzer.Options{Workers: workers}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSequential analyzes 5000 documents one by one
+func BenchmarkSequential(b *testing.B) {
+	benchmarkAnalyze(b, 1)
+}
+
+// BenchmarkParallel analyzes 5000 documents with a worker per CPU
+func BenchmarkParallel(b *testing.B) {
+	benchmarkAnalyze(b, 0)
+}
+
+// BenchmarkAnalyzeWithConfig is the old API that decodes and analyzes every document separately
+func BenchmarkAnalyzeWithConfig(b *testing.B) {
+	manifests := bytes.Split(benchmarkBundle(2500), []byte("\n---"))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, manifest := range manifests {
+			analyzer.AnalyzeWithConfig(manifest, analyzer.Config{})
+		}
+		analyzer.AnalyzeServices(manifests)
+	}
+}
@@ -0,0 +1,281 @@
+package analyzer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	yaml "gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Names of the rules that can be enabled or disabled in config and Options
+const (
+	ReplicasRule         = "replicas"
+	StrategyRule         = "strategy"
+	ReadinessProbeRule   = "readinessProbe"
+	ProbesRule           = "probes"
+	ImageVersionRule     = "imageVersion"
+	ResourcesRule        = "resources"
+	GuaranteedQoSRule    = "guaranteedQoS"
+	GracefulShutdownRule = "gracefulShutdown"
+	ZoneSpreadRule       = "zoneSpread"
+	StorageRule          = "storage"
+	NodePinningRule      = "nodePinning"
+	HostPortsRule        = "hostPorts"
+	PriorityClassRule    = "priorityClass"
+	RolloutRule          = "rollout"
+	JobRule              = "job"
+	CronJobRule          = "cronJob"
+	ControllerRule       = "controller"
+)
+
+// defaultRules lists all rules and whether they are enabled when config does not mention them
+var defaultRules = map[string]bool{
+	ReplicasRule:         true,
+	StrategyRule:         true,
+	ReadinessProbeRule:   true,
+	ProbesRule:           true,
+	ImageVersionRule:     true,
+	ResourcesRule:        true,
+	GuaranteedQoSRule:    false,
+	GracefulShutdownRule: true,
+	ZoneSpreadRule:       false,
+	StorageRule:          true,
+	NodePinningRule:      true,
+	HostPortsRule:        true,
+	PriorityClassRule:    false,
+	RolloutRule:          true,
+	JobRule:              true,
+	CronJobRule:          true,
+	ControllerRule:       true,
+}
+
+const defaultMinReplicas = 2
+const defaultProfileLabel = "haornot/profile"
+
+// Profile is a set of enabled rules and their parameters
+type Profile struct {
+	// Rules enables or disables rules by name. Rules that are not listed use their defaults
+	Rules map[string]bool `yaml:"rules"`
+	// MinReplicas is the minimal number of replicas for workloads
+	MinReplicas int32 `yaml:"minReplicas"`
+	// GuaranteedQoS is a shortcut for enabling guaranteedQoS rule
+	GuaranteedQoS bool `yaml:"guaranteedQoS"`
+	// PersistentPaths are mount paths where emptyDir volumes lose data, e.g. /data. Default is /data and /var/lib
+	PersistentPaths []string `yaml:"persistentPaths"`
+	// PriorityClassSelector selects workloads that must have priorityClassName by labels, e.g. tier=critical.
+	// Empty selector selects all workloads
+	PriorityClassSelector string `yaml:"priorityClassSelector"`
+	// MinPriority is the minimal value of priority classes defined in the input
+	MinPriority int32 `yaml:"minPriority"`
+	// MinReadySeconds is the minimal minReadySeconds of Deployments. Default is 5, and 0 disables the check
+	MinReadySeconds *int32 `yaml:"minReadySeconds"`
+	// MinRevisionHistoryLimit is the minimal revisionHistoryLimit of Deployments. Default is 1, and 0 disables the check
+	MinRevisionHistoryLimit *int32 `yaml:"minRevisionHistoryLimit"`
+	// MaxProgressDeadlineSeconds is the maximal progressDeadlineSeconds of Deployments. Default is 600
+	MaxProgressDeadlineSeconds *int32 `yaml:"maxProgressDeadlineSeconds"`
+}
+
+// Config is the default profile and named profiles that override it
+type Config struct {
+	Profile  `yaml:",inline"`
+	Profiles map[string]Profile `yaml:"profiles"`
+	// DefaultProfile is used for resources that do not select profile with label or namespace
+	DefaultProfile string `yaml:"defaultProfile"`
+	// ProfileLabel is the resource label with the name of the profile
+	ProfileLabel string `yaml:"profileLabel"`
+	// Namespaces maps namespaces to profiles
+	Namespaces map[string]string `yaml:"namespaces"`
+	// CustomRules are checked for all workloads together with built-in rules
+	CustomRules []CustomRule `yaml:"customRules"`
+}
+
+func LoadConfig(path string) (Config, error) {
+	config := Config{}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = yaml.UnmarshalStrict(contents, &config)
+	if err != nil {
+		return config, err
+	}
+	return config, config.Validate()
+}
+
+// Validate checks that config uses only known rules and profiles
+func (c Config) Validate() error {
+	rules, err := c.knownRules()
+	if err != nil {
+		return err
+	}
+	if err := c.Profile.validate(rules); err != nil {
+		return err
+	}
+	for name, profile := range c.Profiles {
+		if err := profile.validate(rules); err != nil {
+			return fmt.Errorf("profile %s: %s", name, err)
+		}
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return fmt.Errorf("unknown profile %s", c.DefaultProfile)
+		}
+	}
+	for namespace, name := range c.Namespaces {
+		if _, ok := c.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %s for namespace %s", name, namespace)
+		}
+	}
+	return nil
+}
+
+// knownRules returns names of built-in and custom rules
+func (c Config) knownRules() (map[string]bool, error) {
+	rules := map[string]bool{}
+	for rule := range defaultRules {
+		rules[rule] = true
+	}
+	for _, rule := range c.CustomRules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		if rules[rule.Name] {
+			return nil, fmt.Errorf("custom rule %s is declared more than once", rule.Name)
+		}
+		rules[rule.Name] = true
+	}
+	return rules, nil
+}
+
+func (p Profile) validate(knownRules map[string]bool) error {
+	for rule := range p.Rules {
+		if !knownRules[rule] {
+			return fmt.Errorf("unknown rule %s", rule)
+		}
+	}
+	if p.MinReplicas < 0 {
+		return fmt.Errorf("minReplicas can not be negative")
+	}
+	if p.minReadySeconds() < 0 || p.minRevisionHistoryLimit() < 0 || p.maxProgressDeadlineSeconds() < 0 {
+		return fmt.Errorf("rollout thresholds can not be negative")
+	}
+	if _, err := labels.Parse(p.PriorityClassSelector); err != nil {
+		return fmt.Errorf("priorityClassSelector is invalid: %s", err)
+	}
+	for _, persistentPath := range p.PersistentPaths {
+		if !path.IsAbs(persistentPath) {
+			return fmt.Errorf("persistent path %s is not absolute", persistentPath)
+		}
+	}
+	return nil
+}
+
+// profileFor picks profile from resource label, then namespace, then the default profile
+func (c Config) profileFor(meta metav1.ObjectMeta) (Profile, error) {
+	label := c.ProfileLabel
+	if label == "" {
+		label = defaultProfileLabel
+	}
+
+	name := c.DefaultProfile
+	if profile, ok := c.Namespaces[meta.Namespace]; ok {
+		name = profile
+	}
+	if profile, ok := meta.Labels[label]; ok {
+		name = profile
+	}
+	if name == "" {
+		return c.Profile, nil
+	}
+
+	named, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %s", name)
+	}
+	return c.Profile.merge(named), nil
+}
+
+func (p Profile) merge(override Profile) Profile {
+	result := p
+	result.Rules = map[string]bool{}
+	result.GuaranteedQoS = p.GuaranteedQoS || override.GuaranteedQoS
+	for rule, enabled := range p.Rules {
+		result.Rules[rule] = enabled
+	}
+	for rule, enabled := range override.Rules {
+		result.Rules[rule] = enabled
+	}
+	if override.MinReplicas != 0 {
+		result.MinReplicas = override.MinReplicas
+	}
+	if override.PersistentPaths != nil {
+		result.PersistentPaths = override.PersistentPaths
+	}
+	if override.PriorityClassSelector != "" {
+		result.PriorityClassSelector = override.PriorityClassSelector
+	}
+	if override.MinPriority != 0 {
+		result.MinPriority = override.MinPriority
+	}
+	if override.MinReadySeconds != nil {
+		result.MinReadySeconds = override.MinReadySeconds
+	}
+	if override.MinRevisionHistoryLimit != nil {
+		result.MinRevisionHistoryLimit = override.MinRevisionHistoryLimit
+	}
+	if override.MaxProgressDeadlineSeconds != nil {
+		result.MaxProgressDeadlineSeconds = override.MaxProgressDeadlineSeconds
+	}
+	return result
+}
+
+func (p Profile) enabled(rule string) bool {
+	if rule == GuaranteedQoSRule && p.GuaranteedQoS {
+		if enabled, ok := p.Rules[rule]; ok {
+			return enabled
+		}
+		return true
+	}
+	if enabled, ok := p.Rules[rule]; ok {
+		return enabled
+	}
+	return defaultRules[rule]
+}
+
+func (p Profile) minReplicas() int32 {
+	if p.MinReplicas == 0 {
+		return defaultMinReplicas
+	}
+	return p.MinReplicas
+}
+
+func (p Profile) persistentPaths() []string {
+	if p.PersistentPaths == nil {
+		return defaultPersistentPaths
+	}
+	return p.PersistentPaths
+}
+
+func (p Profile) minReadySeconds() int32 {
+	if p.MinReadySeconds == nil {
+		return defaultMinReadySeconds
+	}
+	return *p.MinReadySeconds
+}
+
+func (p Profile) minRevisionHistoryLimit() int32 {
+	if p.MinRevisionHistoryLimit == nil {
+		return defaultMinRevisionHistoryLimit
+	}
+	return *p.MinRevisionHistoryLimit
+}
+
+func (p Profile) maxProgressDeadlineSeconds() int32 {
+	if p.MaxProgressDeadlineSeconds == nil {
+		return defaultMaxProgressDeadlineSeconds
+	}
+	return *p.MaxProgressDeadlineSeconds
+}
@@ -0,0 +1,100 @@
+package analyzer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	config := analyzer.Config{
+		Profiles: map[string]analyzer.Profile{
+			"dev":  {MinReplicas: 1},
+			"prod": {MinReplicas: 3, Rules: map[string]bool{"zoneSpread": true}},
+		},
+		Namespaces: map[string]string{"production": "prod"},
+	}
+
+	It("requires 2 replicas by default", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{replicas: "1"}.yaml(), analyzer.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(ConsistOf("At least 2 replicas required for deployment"))
+	})
+
+	It("uses default profile", func() {
+		config.DefaultProfile = "dev"
+		defer func() { config.DefaultProfile = "" }()
+
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{replicas: "1"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("replicas required"))
+	})
+
+	It("picks profile from namespace", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{metadata: "  namespace: production\n", replicas: "2"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(ConsistOf("At least 3 replicas required for deployment"))
+	})
+
+	It("picks profile from label over namespace", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{metadata: "  namespace: production\n  labels:\n    haornot/profile: dev\n", replicas: "1"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("replicas required"))
+	})
+
+	It("enables rules from profile", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{metadata: "  namespace: production\n"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("not spread across zones"))
+	})
+
+	It("returns error when label selects unknown profile", func() {
+		_, err := analyzer.AnalyzeWithConfig(nginxManifest{metadata: "  labels:\n    haornot/profile: staging\n"}.yaml(), config)
+		Expect(err).To(MatchError("unknown profile staging"))
+	})
+
+	Context("LoadConfig", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "haornot")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		load := func(contents string) (analyzer.Config, error) {
+			path := filepath.Join(dir, "haornot.yml")
+			Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+			return analyzer.LoadConfig(path)
+		}
+
+		It("loads profiles", func() {
+			config, err := load("profiles:\n  prod:\n    minReplicas: 3\ndefaultProfile: prod\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Profiles["prod"].MinReplicas).To(BeEquivalentTo(3))
+		})
+
+		It("returns error for unknown rule", func() {
+			_, err := load("profiles:\n  prod:\n    rules:\n      replica: false\n")
+			Expect(err).To(MatchError("profile prod: unknown rule replica"))
+		})
+
+		It("returns error for unknown profile in namespaces", func() {
+			_, err := load("namespaces:\n  production: prod\n")
+			Expect(err).To(MatchError("unknown profile prod for namespace production"))
+		})
+
+		It("returns error for unknown fields", func() {
+			_, err := load("minReplica: 3\n")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,285 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/api/apps/v1"
+	"k8s.io/api/apps/v1beta1"
+	"k8s.io/api/apps/v1beta2"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const removedAPIVersionMessage = "%s %s was removed in Kubernetes %s. Use apps/v1 instead"
+
+// All legacy workload API versions were removed in the same release
+const legacyWorkloadsRemovedIn = "1.16"
+
+// apps/v1beta1 kept 2 old ReplicaSets when revisionHistoryLimit is omitted
+const appsV1beta1RevisionHistoryLimit = 2
+
+// orUnlimited is the value Kubernetes uses for omitted legacy fields without limit, e.g. no progress deadline
+func orUnlimited(value *int32) *int32 {
+	if value != nil {
+		return value
+	}
+	unlimited := int32(math.MaxInt32)
+	return &unlimited
+}
+
+// convertToAppsV1 converts any historical Deployment, StatefulSet, DaemonSet or ReplicaSet to apps/v1.
+// It returns deprecation warning for legacy API versions. Status is not analyzed and is not converted.
+func convertToAppsV1(obj runtime.Object, gvk *schema.GroupVersionKind) (runtime.Object, string, error) {
+	var converted runtime.Object
+	switch in := obj.(type) {
+	case *v1.Deployment, *v1.StatefulSet, *v1.DaemonSet, *v1.ReplicaSet:
+		return in, "", nil
+	case *extensionsv1beta1.Deployment:
+		converted = convertExtensionsDeployment(in)
+	case *v1beta1.Deployment:
+		converted = convertAppsV1beta1Deployment(in)
+	case *v1beta2.Deployment:
+		converted = convertAppsV1beta2Deployment(in)
+	case *v1beta1.StatefulSet:
+		converted = convertAppsV1beta1StatefulSet(in)
+	case *v1beta2.StatefulSet:
+		converted = convertAppsV1beta2StatefulSet(in)
+	case *extensionsv1beta1.DaemonSet:
+		converted = convertExtensionsDaemonSet(in)
+	case *v1beta2.DaemonSet:
+		converted = convertAppsV1beta2DaemonSet(in)
+	case *extensionsv1beta1.ReplicaSet:
+		converted = convertExtensionsReplicaSet(in)
+	case *v1beta2.ReplicaSet:
+		converted = convertAppsV1beta2ReplicaSet(in)
+	default:
+		return nil, "", ErrNotAWorkload
+	}
+
+	warning := fmt.Sprintf(removedAPIVersionMessage, gvk.GroupVersion().String(), gvk.Kind, legacyWorkloadsRemovedIn)
+	return converted, warning, nil
+}
+
+func appsV1TypeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: kind}
+}
+
+// legacySelector applies the defaulting of old API versions that derived the selector from template labels
+func legacySelector(selector *metav1.LabelSelector, templateLabels map[string]string) *metav1.LabelSelector {
+	if selector != nil || len(templateLabels) == 0 {
+		return selector
+	}
+	return &metav1.LabelSelector{MatchLabels: templateLabels}
+}
+
+func convertExtensionsDeployment(in *extensionsv1beta1.Deployment) *v1.Deployment {
+	strategy := v1.DeploymentStrategy{Type: v1.DeploymentStrategyType(in.Spec.Strategy.Type)}
+	if strategy.Type == "" {
+		strategy.Type = v1.RollingUpdateDeploymentStrategyType
+	}
+	if strategy.Type == v1.RollingUpdateDeploymentStrategyType {
+		// extensions/v1beta1 defaulted both parameters to 1 instead of 25%
+		one := intstr.FromInt(1)
+		strategy.RollingUpdate = &v1.RollingUpdateDeployment{MaxUnavailable: &one, MaxSurge: &one}
+		if in.Spec.Strategy.RollingUpdate != nil {
+			if in.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+				strategy.RollingUpdate.MaxUnavailable = in.Spec.Strategy.RollingUpdate.MaxUnavailable
+			}
+			if in.Spec.Strategy.RollingUpdate.MaxSurge != nil {
+				strategy.RollingUpdate.MaxSurge = in.Spec.Strategy.RollingUpdate.MaxSurge
+			}
+		}
+	}
+
+	// extensions/v1beta1 kept all old ReplicaSets and did not have progress deadline by default
+	return &v1.Deployment{
+		TypeMeta:   appsV1TypeMeta("Deployment"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.DeploymentSpec{
+			Replicas:                in.Spec.Replicas,
+			Selector:                legacySelector(in.Spec.Selector, in.Spec.Template.Labels),
+			Template:                in.Spec.Template,
+			Strategy:                strategy,
+			MinReadySeconds:         in.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    orUnlimited(in.Spec.RevisionHistoryLimit),
+			Paused:                  in.Spec.Paused,
+			ProgressDeadlineSeconds: orUnlimited(in.Spec.ProgressDeadlineSeconds),
+		},
+	}
+}
+
+func convertAppsV1beta1Deployment(in *v1beta1.Deployment) *v1.Deployment {
+	strategy := v1.DeploymentStrategy{Type: v1.DeploymentStrategyType(in.Spec.Strategy.Type)}
+	if in.Spec.Strategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateDeployment{
+			MaxUnavailable: in.Spec.Strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       in.Spec.Strategy.RollingUpdate.MaxSurge,
+		}
+	}
+
+	revisionHistoryLimit := in.Spec.RevisionHistoryLimit
+	if revisionHistoryLimit == nil {
+		limit := int32(appsV1beta1RevisionHistoryLimit)
+		revisionHistoryLimit = &limit
+	}
+
+	return &v1.Deployment{
+		TypeMeta:   appsV1TypeMeta("Deployment"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.DeploymentSpec{
+			Replicas:                in.Spec.Replicas,
+			Selector:                legacySelector(in.Spec.Selector, in.Spec.Template.Labels),
+			Template:                in.Spec.Template,
+			Strategy:                strategy,
+			MinReadySeconds:         in.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    revisionHistoryLimit,
+			Paused:                  in.Spec.Paused,
+			ProgressDeadlineSeconds: in.Spec.ProgressDeadlineSeconds,
+		},
+	}
+}
+
+func convertAppsV1beta2Deployment(in *v1beta2.Deployment) *v1.Deployment {
+	strategy := v1.DeploymentStrategy{Type: v1.DeploymentStrategyType(in.Spec.Strategy.Type)}
+	if in.Spec.Strategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateDeployment{
+			MaxUnavailable: in.Spec.Strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       in.Spec.Strategy.RollingUpdate.MaxSurge,
+		}
+	}
+
+	return &v1.Deployment{
+		TypeMeta:   appsV1TypeMeta("Deployment"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.DeploymentSpec{
+			Replicas:                in.Spec.Replicas,
+			Selector:                in.Spec.Selector,
+			Template:                in.Spec.Template,
+			Strategy:                strategy,
+			MinReadySeconds:         in.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    in.Spec.RevisionHistoryLimit,
+			Paused:                  in.Spec.Paused,
+			ProgressDeadlineSeconds: in.Spec.ProgressDeadlineSeconds,
+		},
+	}
+}
+
+func convertAppsV1beta1StatefulSet(in *v1beta1.StatefulSet) *v1.StatefulSet {
+	strategy := v1.StatefulSetUpdateStrategy{Type: v1.StatefulSetUpdateStrategyType(in.Spec.UpdateStrategy.Type)}
+	if strategy.Type == "" {
+		// apps/v1beta1 defaulted to OnDelete instead of RollingUpdate
+		strategy.Type = v1.OnDeleteStatefulSetStrategyType
+	}
+	if in.Spec.UpdateStrategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateStatefulSetStrategy{Partition: in.Spec.UpdateStrategy.RollingUpdate.Partition}
+	}
+
+	return &v1.StatefulSet{
+		TypeMeta:   appsV1TypeMeta("StatefulSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.StatefulSetSpec{
+			Replicas:             in.Spec.Replicas,
+			Selector:             legacySelector(in.Spec.Selector, in.Spec.Template.Labels),
+			Template:             in.Spec.Template,
+			VolumeClaimTemplates: in.Spec.VolumeClaimTemplates,
+			ServiceName:          in.Spec.ServiceName,
+			PodManagementPolicy:  v1.PodManagementPolicyType(in.Spec.PodManagementPolicy),
+			UpdateStrategy:       strategy,
+			RevisionHistoryLimit: in.Spec.RevisionHistoryLimit,
+		},
+	}
+}
+
+func convertAppsV1beta2StatefulSet(in *v1beta2.StatefulSet) *v1.StatefulSet {
+	strategy := v1.StatefulSetUpdateStrategy{Type: v1.StatefulSetUpdateStrategyType(in.Spec.UpdateStrategy.Type)}
+	if in.Spec.UpdateStrategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateStatefulSetStrategy{Partition: in.Spec.UpdateStrategy.RollingUpdate.Partition}
+	}
+
+	return &v1.StatefulSet{
+		TypeMeta:   appsV1TypeMeta("StatefulSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.StatefulSetSpec{
+			Replicas:             in.Spec.Replicas,
+			Selector:             in.Spec.Selector,
+			Template:             in.Spec.Template,
+			VolumeClaimTemplates: in.Spec.VolumeClaimTemplates,
+			ServiceName:          in.Spec.ServiceName,
+			PodManagementPolicy:  v1.PodManagementPolicyType(in.Spec.PodManagementPolicy),
+			UpdateStrategy:       strategy,
+			RevisionHistoryLimit: in.Spec.RevisionHistoryLimit,
+		},
+	}
+}
+
+func convertExtensionsDaemonSet(in *extensionsv1beta1.DaemonSet) *v1.DaemonSet {
+	strategy := v1.DaemonSetUpdateStrategy{Type: v1.DaemonSetUpdateStrategyType(in.Spec.UpdateStrategy.Type)}
+	if strategy.Type == "" {
+		// extensions/v1beta1 defaulted to OnDelete instead of RollingUpdate
+		strategy.Type = v1.OnDeleteDaemonSetStrategyType
+	}
+	if in.Spec.UpdateStrategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateDaemonSet{MaxUnavailable: in.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable}
+	}
+
+	return &v1.DaemonSet{
+		TypeMeta:   appsV1TypeMeta("DaemonSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.DaemonSetSpec{
+			Selector:             legacySelector(in.Spec.Selector, in.Spec.Template.Labels),
+			Template:             in.Spec.Template,
+			UpdateStrategy:       strategy,
+			MinReadySeconds:      in.Spec.MinReadySeconds,
+			RevisionHistoryLimit: in.Spec.RevisionHistoryLimit,
+		},
+	}
+}
+
+func convertAppsV1beta2DaemonSet(in *v1beta2.DaemonSet) *v1.DaemonSet {
+	strategy := v1.DaemonSetUpdateStrategy{Type: v1.DaemonSetUpdateStrategyType(in.Spec.UpdateStrategy.Type)}
+	if in.Spec.UpdateStrategy.RollingUpdate != nil {
+		strategy.RollingUpdate = &v1.RollingUpdateDaemonSet{MaxUnavailable: in.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable}
+	}
+
+	return &v1.DaemonSet{
+		TypeMeta:   appsV1TypeMeta("DaemonSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.DaemonSetSpec{
+			Selector:             in.Spec.Selector,
+			Template:             in.Spec.Template,
+			UpdateStrategy:       strategy,
+			MinReadySeconds:      in.Spec.MinReadySeconds,
+			RevisionHistoryLimit: in.Spec.RevisionHistoryLimit,
+		},
+	}
+}
+
+func convertExtensionsReplicaSet(in *extensionsv1beta1.ReplicaSet) *v1.ReplicaSet {
+	return &v1.ReplicaSet{
+		TypeMeta:   appsV1TypeMeta("ReplicaSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.ReplicaSetSpec{
+			Replicas:        in.Spec.Replicas,
+			MinReadySeconds: in.Spec.MinReadySeconds,
+			Selector:        legacySelector(in.Spec.Selector, in.Spec.Template.Labels),
+			Template:        in.Spec.Template,
+		},
+	}
+}
+
+func convertAppsV1beta2ReplicaSet(in *v1beta2.ReplicaSet) *v1.ReplicaSet {
+	return &v1.ReplicaSet{
+		TypeMeta:   appsV1TypeMeta("ReplicaSet"),
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1.ReplicaSetSpec{
+			Replicas:        in.Spec.Replicas,
+			MinReadySeconds: in.Spec.MinReadySeconds,
+			Selector:        in.Spec.Selector,
+			Template:        in.Spec.Template,
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/google/cel-go/cel"
+)
+
+const customRuleEvaluationMessage = "Rule %s can not be evaluated: %s"
+
+// Severities of custom rules and policies
+const (
+	// CriticalSeverity is for problems that cause downtime on their own
+	CriticalSeverity = "critical"
+	ErrorSeverity    = "error"
+	WarningSeverity  = "warning"
+)
+
+// CustomRule is a CEL expression that must be true for every workload.
+// Custom rules are enabled by default and can be disabled in profiles by name, same as built-in rules.
+type CustomRule struct {
+	Name string `yaml:"name"`
+	// Expression has the workload manifest in `object` variable, e.g. `'team' in object.metadata.labels`
+	Expression string `yaml:"expression"`
+	// Message is a text/template with Kind, Name, Namespace and Object of the workload
+	Message string `yaml:"message"`
+	// Severity is critical, error or warning. Warnings do not fail the check. Default is error
+	Severity string `yaml:"severity"`
+}
+
+type compiledRule struct {
+	program cel.Program
+	message *template.Template
+}
+
+var (
+	compiledRulesLock sync.Mutex
+	compiledRules     = map[CustomRule]*compiledRule{}
+)
+
+// compile compiles rule once, so every workload reuses the program
+func (r CustomRule) compile() (*compiledRule, error) {
+	compiledRulesLock.Lock()
+	defer compiledRulesLock.Unlock()
+	if compiled, ok := compiledRules[r]; ok {
+		return compiled, nil
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(r.Expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if !cel.BoolType.IsAssignableType(ast.OutputType()) {
+		return nil, fmt.Errorf("expression returns %s instead of bool", ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	message, err := template.New(r.Name).Option("missingkey=zero").Parse(r.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledRule{program: program, message: message}
+	compiledRules[r] = compiled
+	return compiled, nil
+}
+
+func (r CustomRule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("custom rule name is required")
+	}
+	if _, ok := defaultRules[r.Name]; ok {
+		return fmt.Errorf("custom rule %s has the same name as built-in rule", r.Name)
+	}
+	if _, ok := severityWeights[r.severity()]; !ok {
+		return fmt.Errorf("custom rule %s has unknown severity %s", r.Name, r.Severity)
+	}
+	if _, err := r.compile(); err != nil {
+		return fmt.Errorf("custom rule %s: %s", r.Name, err)
+	}
+	return nil
+}
+
+// evaluate returns message when workload does not satisfy the rule
+func (r CustomRule) evaluate(w *workload) (string, bool) {
+	compiled, err := r.compile()
+	if err != nil {
+		return fmt.Sprintf(customRuleEvaluationMessage, r.Name, err), true
+	}
+
+	out, _, err := compiled.program.Eval(map[string]interface{}{"object": w.fields})
+	if err != nil {
+		return fmt.Sprintf(customRuleEvaluationMessage, r.Name, err), true
+	}
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return fmt.Sprintf(customRuleEvaluationMessage, r.Name, fmt.Sprintf("result %v is not bool", out.Value())), true
+	}
+	if passed {
+		return "", false
+	}
+
+	message := &bytes.Buffer{}
+	data := struct {
+		Kind      string
+		Name      string
+		Namespace string
+		Object    map[string]interface{}
+	}{w.Kind, w.Name, w.Namespace, w.fields}
+	if err := compiled.message.Execute(message, data); err != nil {
+		return fmt.Sprintf(customRuleEvaluationMessage, r.Name, err), true
+	}
+	return message.String(), true
+}
+
+func analyzeCustomRules(w *workload, profile Profile, rules []CustomRule, card *scorecard) (errors []string, warnings []string) {
+	for _, rule := range rules {
+		if enabled, ok := profile.Rules[rule.Name]; ok && !enabled {
+			continue
+		}
+		messages := []string{}
+		if message, failed := rule.evaluate(w); failed {
+			messages = append(messages, message)
+		}
+		card.check(rule.severity(), messages)
+		if rule.severity() == WarningSeverity {
+			warnings = append(warnings, messages...)
+		} else {
+			errors = append(errors, messages...)
+		}
+	}
+	return errors, warnings
+}
+
+func (r CustomRule) severity() string {
+	if r.Severity == "" {
+		return ErrorSeverity
+	}
+	return r.Severity
+}
@@ -0,0 +1,133 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Custom rules", func() {
+	deployment := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: web
+  labels:
+    app: nginx
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      priorityClassName: best-effort
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`)
+
+	teamLabel := analyzer.CustomRule{
+		Name:       "teamLabel",
+		Expression: "has(object.metadata.labels) && 'team' in object.metadata.labels",
+		Message:    "{{.Kind}} {{.Namespace}}/{{.Name}} does not have team label",
+	}
+	priorityClass := analyzer.CustomRule{
+		Name:       "priorityClass",
+		Expression: "object.spec.template.spec.priorityClassName in ['critical', 'high']",
+		Message:    "Priority class {{.Object.spec.template.spec.priorityClassName}} is not approved",
+		Severity:   analyzer.WarningSeverity,
+	}
+
+	It("returns message when expression is false", func() {
+		output, err := analyzer.AnalyzeWithConfig(deployment, analyzer.Config{CustomRules: []analyzer.CustomRule{teamLabel}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Deployment web/nginx does not have team label"))
+	})
+
+	It("does not return message when expression is true", func() {
+		rule := teamLabel
+		rule.Expression = "object.metadata.labels.app == 'nginx'"
+		output, err := analyzer.AnalyzeWithConfig(deployment, analyzer.Config{CustomRules: []analyzer.CustomRule{rule}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("team label"))
+	})
+
+	It("returns message for workload without enough replicas", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinReplicas: 5}, CustomRules: []analyzer.CustomRule{teamLabel}}
+		output, err := analyzer.AnalyzeWithConfig(deployment, config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("At least 5 replicas required for deployment"))
+		Expect(output).To(HaveMatchingElement("Deployment web/nginx does not have team label"))
+		Expect(output.Score).To(Equal(0))
+
+		rule := teamLabel
+		rule.Expression = "object.metadata.labels.app == 'nginx'"
+		config.CustomRules = []analyzer.CustomRule{rule}
+		output, err = analyzer.AnalyzeWithConfig(deployment, config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Score).To(BeNumerically(">", 0))
+	})
+
+	It("returns warnings for rules with warning severity", func() {
+		output, err := analyzer.AnalyzeWithConfig(deployment, analyzer.Config{CustomRules: []analyzer.CustomRule{priorityClass}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).NotTo(ContainElement(ContainSubstring("Priority class")))
+		Expect(output.Warnings).To(ContainElement("Priority class best-effort is not approved"))
+	})
+
+	It("can be disabled in profile", func() {
+		config := analyzer.Config{
+			CustomRules:    []analyzer.CustomRule{teamLabel},
+			Profiles:       map[string]analyzer.Profile{"dev": {Rules: map[string]bool{"teamLabel": false}}},
+			DefaultProfile: "dev",
+		}
+		Expect(config.Validate()).To(Succeed())
+		output, err := analyzer.AnalyzeWithConfig(deployment, config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("team label"))
+	})
+
+	It("returns message when expression can not be evaluated", func() {
+		rule := teamLabel
+		rule.Expression = "object.metadata.labels.team == 'web'"
+		output, err := analyzer.AnalyzeWithConfig(deployment, analyzer.Config{CustomRules: []analyzer.CustomRule{rule}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Rule teamLabel can not be evaluated"))
+	})
+
+	Context("validation", func() {
+		It("returns error for invalid expression", func() {
+			rule := teamLabel
+			rule.Expression = "object.metadata.labels["
+			err := analyzer.Config{CustomRules: []analyzer.CustomRule{rule}}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("custom rule teamLabel")))
+		})
+
+		It("returns error when expression does not return bool", func() {
+			rule := teamLabel
+			rule.Expression = "'team'"
+			err := analyzer.Config{CustomRules: []analyzer.CustomRule{rule}}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("instead of bool")))
+		})
+
+		It("returns error for built-in rule name", func() {
+			rule := teamLabel
+			rule.Name = "replicas"
+			err := analyzer.Config{CustomRules: []analyzer.CustomRule{rule}}.Validate()
+			Expect(err).To(MatchError("custom rule replicas has the same name as built-in rule"))
+		})
+
+		It("returns error for duplicate names", func() {
+			err := analyzer.Config{CustomRules: []analyzer.CustomRule{teamLabel, teamLabel}}.Validate()
+			Expect(err).To(MatchError("custom rule teamLabel is declared more than once"))
+		})
+
+		It("returns error for unknown severity", func() {
+			rule := teamLabel
+			rule.Severity = "fatal"
+			err := analyzer.Config{CustomRules: []analyzer.CustomRule{rule}}.Validate()
+			Expect(err).To(MatchError("custom rule teamLabel has unknown severity fatal"))
+		})
+	})
+})
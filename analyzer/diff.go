@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/alex-slynko/haornot/types"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	replicasRegressionMessage = "Replicas decreased from %d to %d"
+	scoreRegressionMessage    = "HA score decreased from %d to %d"
+)
+
+// Diff analyzes objects before and after a change and keeps only what the change made worse:
+// findings that are new, errors for workloads that lost replicas and warnings for resources with lower score.
+// Resources that are not in the base are new, so all their findings are kept. Removed resources are not reported.
+func Diff(base, changed []runtime.Object, options Options) (*Result, error) {
+	before, err := AnalyzeObjects(base, options)
+	if err != nil {
+		return nil, err
+	}
+	after, err := AnalyzeObjects(changed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]*types.Message{}
+	for _, messages := range [][]*types.Message{before.Workloads, before.Services, before.Others} {
+		for _, msg := range messages {
+			previous[messageKey(msg)] = msg
+		}
+	}
+	previousReplicas := replicasOf(base)
+	replicas := replicasOf(changed)
+
+	result := &Result{Failures: newFailures(before.Failures, after.Failures)}
+	for _, msg := range after.Workloads {
+		diffed := diffMessage(previous[messageKey(msg)], msg)
+		if old, ok := previousReplicas[messageKey(msg)]; ok && replicas[messageKey(msg)] < old {
+			diffed.Errors = append(diffed.Errors, fmt.Sprintf(replicasRegressionMessage, old, replicas[messageKey(msg)]))
+		}
+		result.Workloads = append(result.Workloads, diffed)
+	}
+	for _, msg := range after.Services {
+		result.Services = append(result.Services, diffMessage(previous[messageKey(msg)], msg))
+	}
+	for _, msg := range after.Others {
+		result.Others = append(result.Others, diffMessage(previous[messageKey(msg)], msg))
+	}
+	return result, nil
+}
+
+// messageKey identifies resource in both versions. Resources with the same name from different sources are compared separately
+func messageKey(msg *types.Message) string {
+	return msg.Source + ":" + msg.Kind + "/" + msg.Namespace + "/" + msg.Name
+}
+
+// diffMessage copies msg with findings that are not in the previous message
+func diffMessage(previous, msg *types.Message) *types.Message {
+	diffed := *msg
+	if previous == nil {
+		return &diffed
+	}
+	diffed.Errors = newFindings(previous.Errors, msg.Errors)
+	diffed.Warnings = newFindings(previous.Warnings, msg.Warnings)
+	if msg.Score < previous.Score {
+		diffed.Warnings = append(diffed.Warnings, fmt.Sprintf(scoreRegressionMessage, previous.Score, msg.Score))
+	}
+	return &diffed
+}
+
+func newFindings(previous, findings []string) []string {
+	known := map[string]bool{}
+	for _, finding := range previous {
+		known[finding] = true
+	}
+	result := []string{}
+	for _, finding := range findings {
+		if !known[finding] {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+func newFailures(previous, failures []error) []error {
+	known := map[string]bool{}
+	for _, err := range previous {
+		known[failureKey(err)] = true
+	}
+	result := []error{}
+	for _, err := range failures {
+		if !known[failureKey(err)] {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+func failureKey(err error) string {
+	if objectErr, ok := err.(*ObjectError); ok {
+		return messageKey(&types.Message{Kind: objectErr.Kind, Namespace: objectErr.Namespace, Name: objectErr.Name, Source: objectErr.Source}) + ": " + err.Error()
+	}
+	return err.Error()
+}
+
+// replicasOf returns replicas of Deployments and StatefulSets by message key
+func replicasOf(objects []runtime.Object) map[string]int32 {
+	replicas := map[string]int32{}
+	for _, obj := range objects {
+		o, err := newObject(obj)
+		if err != nil {
+			continue
+		}
+		w, err := newWorkload(o)
+		if err != nil || !ruleApplies(ReplicasRule, w) {
+			continue
+		}
+		// Kubernetes runs 1 replica when replicas are not specified
+		count := int32(1)
+		if w.Replicas != nil {
+			count = *w.Replicas
+		}
+		replicas[messageKey(&types.Message{Kind: w.Kind, Namespace: w.Namespace, Name: w.Name, Source: w.Source})] = count
+	}
+	return replicas
+}
@@ -0,0 +1,89 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Diff", func() {
+	var base, changed *appsv1.Deployment
+
+	BeforeEach(func() {
+		replicas := int32(3)
+		base = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "api", Image: "api:latest"}},
+					},
+				},
+			},
+		}
+		changed = base.DeepCopy()
+	})
+
+	It("reports only new findings", func() {
+		replicas := int32(1)
+		changed.Spec.Replicas = &replicas
+
+		result, err := analyzer.Diff([]runtime.Object{base}, []runtime.Object{changed}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads).To(HaveLen(1))
+		Expect(result.Workloads[0].Errors).To(ConsistOf(
+			"At least 2 replicas required for deployment",
+			"Replicas decreased from 3 to 1",
+		))
+		Expect(result.Workloads[0].Warnings).To(ConsistOf(ContainSubstring("HA score decreased")))
+	})
+
+	It("reports replicas regression that satisfies the minimum", func() {
+		replicas := int32(2)
+		changed.Spec.Replicas = &replicas
+
+		result, err := analyzer.Diff([]runtime.Object{base}, []runtime.Object{changed}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0].Errors).To(ConsistOf("Replicas decreased from 3 to 2"))
+		Expect(result.HasErrors()).To(BeTrue())
+	})
+
+	It("passes when the change does not make anything worse", func() {
+		changed.Labels = map[string]string{"team": "payments"}
+
+		result, err := analyzer.Diff([]runtime.Object{base}, []runtime.Object{changed}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.HasErrors()).To(BeFalse())
+		Expect(result.Workloads[0].Warnings).To(BeEmpty())
+	})
+
+	It("reports all findings of new resources", func() {
+		changed.Name = "web"
+
+		result, err := analyzer.Diff([]runtime.Object{base}, []runtime.Object{changed}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.HasErrors()).To(BeTrue())
+		Expect(result.Workloads[0].Name).To(Equal("web"))
+	})
+
+	It("compares resources with the same name from different sources separately", func() {
+		base.Annotations = map[string]string{analyzer.SourceAnnotation: "prod/api.yml"}
+		changed.Annotations = map[string]string{analyzer.SourceAnnotation: "dev/api.yml"}
+		replicas := int32(1)
+		changed.Spec.Replicas = &replicas
+
+		result, err := analyzer.Diff([]runtime.Object{base}, []runtime.Object{base, changed}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads).To(HaveLen(2))
+		Expect(result.Workloads[0].Errors).To(BeEmpty())
+		Expect(result.Workloads[1].Source).To(Equal("dev/api.yml"))
+		Expect(result.Workloads[1].Errors).To(ContainElement("At least 2 replicas required for deployment"))
+		Expect(result.Workloads[1].Errors).NotTo(ContainElement(ContainSubstring("Replicas decreased")))
+	})
+})
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alex-slynko/haornot/types"
+)
+
+// workloadFixes returns automatic fixes for errors that are resolved by setting one field
+func workloadFixes(w *workload, profile Profile, errors []string) []types.Fix {
+	fixes := []types.Fix{}
+	for _, e := range errors {
+		switch e {
+		case fmt.Sprintf(notEnoughReplicasMessage, profile.minReplicas(), strings.ToLower(w.Kind)):
+			fixes = append(fixes, types.Fix{Finding: e, Path: []string{"spec", "replicas"}, Value: fmt.Sprint(profile.minReplicas())})
+		case recreateStrategyMessage:
+			fixes = append(fixes, types.Fix{Finding: e, Path: []string{"spec", "strategy", "type"}, Value: "RollingUpdate"})
+		case zeroGracePeriodMessage:
+			fixes = append(fixes, types.Fix{Finding: e, Path: append(podSpecPath(w.Kind), "terminationGracePeriodSeconds"), Value: fmt.Sprint(defaultTerminationGracePeriodSeconds)})
+		case concurrentRunsMessage:
+			fixes = append(fixes, types.Fix{Finding: e, Path: []string{"spec", "concurrencyPolicy"}, Value: "Forbid"})
+		case jobRestartPolicyMissingMessage, fmt.Sprintf(jobRestartPolicyMessage, w.Template.Spec.RestartPolicy):
+			fixes = append(fixes, types.Fix{Finding: e, Path: append(podSpecPath(w.Kind), "restartPolicy"), Value: "OnFailure"})
+		}
+	}
+	return fixes
+}
+
+// podSpecPath is the path of the pod spec in the manifest of the kind
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Pod":
+		return []string{"spec"}
+	}
+	return []string{"spec", "template", "spec"}
+}
@@ -0,0 +1,35 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fixes", func() {
+	It("sets replicas to the minimum of the profile", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{replicas: "1", spec: "  strategy:\n    type: Recreate", podSpec: "      terminationGracePeriodSeconds: 0"}.yaml(), analyzer.Config{Profile: analyzer.Profile{MinReplicas: 3}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Fixes).To(Equal([]types.Fix{
+			{Finding: "At least 3 replicas required for deployment", Path: []string{"spec", "replicas"}, Value: "3"},
+		}))
+	})
+
+	It("switches to rolling update and restores grace period", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: "  strategy:\n    type: Recreate", podSpec: "      terminationGracePeriodSeconds: 0"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Fixes).To(ConsistOf(
+			types.Fix{Finding: "Deployment uses Recreate strategy. All pods are stopped on every rollout", Path: []string{"spec", "strategy", "type"}, Value: "RollingUpdate"},
+			types.Fix{Finding: "terminationGracePeriodSeconds is 0. Pods are killed without graceful shutdown", Path: []string{"spec", "template", "spec", "terminationGracePeriodSeconds"}, Value: "30"},
+		))
+	})
+
+	It("does not fix errors without automatic fixes", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: "  strategy:\n    type: RollingUpdate", podSpec: "      terminationGracePeriodSeconds: 0"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(ContainElement(ContainSubstring("readiness probe")))
+		Expect(output.Fixes).To(HaveLen(1))
+		Expect(output.Fixes[0].Path).To(ContainElement("terminationGracePeriodSeconds"))
+	})
+})
@@ -0,0 +1,119 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	msg "github.com/alex-slynko/haornot/types"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// threshold is a profile threshold for tests, where 0 is set explicitly
+func threshold(value int32) *int32 {
+	return &value
+}
+
+func HaveMatchingElement(expected interface{}) types.GomegaMatcher {
+	return &matchingElementMatcher{
+		expected: expected,
+	}
+}
+
+type matchingElementMatcher struct {
+	expected interface{}
+}
+
+func (matcher *matchingElementMatcher) Match(actual interface{}) (success bool, err error) {
+	if actual == nil {
+		return false, nil
+	}
+	array := actual.(*msg.Message).Errors
+
+	substring := matcher.expected.(string)
+	for _, element := range array {
+		if strings.Contains(element, substring) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (matcher *matchingElementMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected\n\t%v\nto contain the element that matches\n\t%s", actual, matcher.expected)
+}
+
+func (matcher *matchingElementMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected\n\t%v\nnot to contain the element that matches\n\t%s", actual, matcher.expected)
+}
+
+// decode decodes manifest that is expected to be valid
+func decode(manifest string) runtime.Object {
+	obj, err := analyzer.Decode([]byte(manifest))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return obj
+}
+
+// workloadErrors analyzes objects together and returns errors of the only workload among them
+func workloadErrors(options analyzer.Options, objects ...runtime.Object) []string {
+	result, err := analyzer.AnalyzeObjects(objects, options)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, result.Failures).To(BeEmpty())
+	ExpectWithOffset(1, result.Workloads).To(HaveLen(1))
+	return result.Workloads[0].Errors
+}
+
+// nginxManifest is nginx workload for tests. Fields are YAML lines with indentation of their section
+type nginxManifest struct {
+	// apiVersion is apps/v1 when empty
+	apiVersion string
+	// kind is Deployment when empty
+	kind string
+	// replicas are 3 when empty
+	replicas  string
+	metadata  string
+	spec      string
+	podSpec   string
+	container string
+}
+
+func (m nginxManifest) yaml() []byte {
+	apiVersion := m.apiVersion
+	if apiVersion == "" {
+		apiVersion = "apps/v1"
+	}
+	kind := m.kind
+	if kind == "" {
+		kind = "Deployment"
+	}
+	replicas := m.replicas
+	if replicas == "" {
+		replicas = "3"
+	}
+	return []byte(`apiVersion: ` + apiVersion + `
+kind: ` + kind + `
+metadata:
+  name: nginx
+` + lines(m.metadata) + `spec:
+  replicas: ` + replicas + `
+` + lines(m.spec) + `  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+` + lines(m.podSpec) + `      containers:
+      - name: nginx
+        image: nginx:1.15
+` + lines(m.container))
+}
+
+// lines ends YAML fields with new line, so they can be followed by the rest of the manifest
+func lines(fields string) string {
+	if fields == "" || strings.HasSuffix(fields, "\n") {
+		return fields
+	}
+	return fields + "\n"
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const hostPortsMessage = "Pods use host ports %s. Only one pod can run on a node, so %d nodes are required for %d replicas"
+const hostPortsSurgeMessage = "Pods use host ports %s. Only one pod can run on a node, so %d nodes are required for %d replicas and %d pods created by maxSurge during rollout"
+
+// analyzeHostPorts reports how many nodes are required when pods bind ports on the node
+func analyzeHostPorts(w *workload, replicas int32) []string {
+	spec := w.Template.Spec
+	ports := []string{}
+	for _, c := range spec.Containers {
+		for _, p := range c.Ports {
+			port := p.HostPort
+			// Pods with hostNetwork listen on container ports of the node
+			if spec.HostNetwork && port == 0 {
+				port = p.ContainerPort
+			}
+			if port != 0 {
+				ports = append(ports, fmt.Sprint(port))
+			}
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	surge := 0
+	if w.Deployment != nil {
+		surge = surgePods(w.Deployment.Spec.Strategy, replicas)
+	}
+	if surge == 0 {
+		return []string{fmt.Sprintf(hostPortsMessage, strings.Join(ports, ", "), replicas, replicas)}
+	}
+	return []string{fmt.Sprintf(hostPortsSurgeMessage, strings.Join(ports, ", "), int(replicas)+surge, replicas, surge)}
+}
@@ -0,0 +1,42 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze host ports", func() {
+	It("returns nodes required for replicas and maxSurge", func() {
+		output, err := analyzer.Analyze(nginxManifest{replicas: "4", container: "        ports:\n        - {containerPort: 80, hostPort: 8080}"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pods use host ports 8080. Only one pod can run on a node, so 5 nodes are required for 4 replicas and 1 pods created by maxSurge during rollout"))
+	})
+
+	It("uses container ports with hostNetwork", func() {
+		output, err := analyzer.Analyze(nginxManifest{replicas: "4", spec: `  strategy:
+    rollingUpdate:
+      maxSurge: 2
+      maxUnavailable: 1`, podSpec: "      hostNetwork: true", container: "        ports:\n        - {containerPort: 80}"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pods use host ports 80. Only one pod can run on a node, so 6 nodes are required for 4 replicas and 2 pods"))
+	})
+
+	It("does not count surge for StatefulSets", func() {
+		output, err := analyzer.Analyze(nginxManifest{kind: "StatefulSet", replicas: "4", container: "        ports:\n        - {containerPort: 80, hostPort: 80}"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(ContainElement("Pods use host ports 80. Only one pod can run on a node, so 4 nodes are required for 4 replicas"))
+	})
+
+	It("is successful without host ports", func() {
+		output, err := analyzer.Analyze(nginxManifest{replicas: "4", container: "        ports:\n        - {containerPort: 80}"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("host ports"))
+	})
+
+	It("does not check DaemonSets", func() {
+		output, err := analyzer.Analyze(nginxManifest{kind: "DaemonSet", replicas: "4", container: "        ports:\n        - {containerPort: 80, hostPort: 80}"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("host ports"))
+	})
+})
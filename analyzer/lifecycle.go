@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const preStopMissingMessage = "Pod %s exposes ports but does not have preStop hook. Connections will be dropped during rollout"
+const gracePeriodShorterThanSleepMessage = "terminationGracePeriodSeconds %d does not leave time to shut down after preStop sleep of %d seconds in pod %s"
+const zeroGracePeriodMessage = "terminationGracePeriodSeconds is 0. Pods are killed without graceful shutdown"
+
+const defaultTerminationGracePeriodSeconds = 30
+
+var sleepCommand = regexp.MustCompile(`\bsleep\s+(\d+)s?\b`)
+
+func analyzeShutdown(spec corev1.PodSpec) []string {
+	errors := []string{}
+
+	gracePeriod := int64(defaultTerminationGracePeriodSeconds)
+	if spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *spec.TerminationGracePeriodSeconds
+	}
+	if gracePeriod == 0 {
+		errors = append(errors, zeroGracePeriodMessage)
+	}
+
+	for _, c := range spec.Containers {
+		var preStop *corev1.Handler
+		if c.Lifecycle != nil {
+			preStop = c.Lifecycle.PreStop
+		}
+
+		if preStop == nil {
+			if len(c.Ports) > 0 {
+				errors = append(errors, fmt.Sprintf(preStopMissingMessage, c.Name))
+			}
+			continue
+		}
+
+		if sleep := preStopSleep(preStop); gracePeriod > 0 && sleep >= gracePeriod {
+			errors = append(errors, fmt.Sprintf(gracePeriodShorterThanSleepMessage, gracePeriod, sleep, c.Name))
+		}
+	}
+	return errors
+}
+
+func preStopSleep(handler *corev1.Handler) int64 {
+	if handler.Exec == nil {
+		return 0
+	}
+	match := sleepCommand.FindStringSubmatch(strings.Join(handler.Exec.Command, " "))
+	if match == nil {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seconds
+}
@@ -0,0 +1,60 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze graceful shutdown", func() {
+	deploymentWithPodSpec := func(gracePeriod, lifecycle string) []byte {
+		return nginxManifest{podSpec: gracePeriod, container: "        ports:\n        - containerPort: 80\n" + lifecycle}.yaml()
+	}
+
+	It("is successful when preStop hook fits into grace period", func() {
+		output, err := analyzer.Analyze(deploymentWithPodSpec("", `        lifecycle:
+          preStop:
+            exec:
+              command: ["/bin/sh", "-c", "sleep 10"]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("preStop"))
+		Expect(output).NotTo(HaveMatchingElement("terminationGracePeriodSeconds"))
+	})
+
+	It("returns message when container with ports does not have preStop hook", func() {
+		output, err := analyzer.Analyze(deploymentWithPodSpec("", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pod nginx exposes ports but does not have preStop hook"))
+	})
+
+	It("returns message when grace period is shorter than preStop sleep", func() {
+		output, err := analyzer.Analyze(deploymentWithPodSpec("      terminationGracePeriodSeconds: 10", `        lifecycle:
+          preStop:
+            exec:
+              command: ["sleep", "15"]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("terminationGracePeriodSeconds 10 does not leave time to shut down after preStop sleep of 15 seconds"))
+	})
+
+	It("compares preStop sleep with default grace period", func() {
+		output, err := analyzer.Analyze(deploymentWithPodSpec("", `        lifecycle:
+          preStop:
+            exec:
+              command: ["sleep", "45s"]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("terminationGracePeriodSeconds 30"))
+	})
+
+	It("returns message when grace period is 0", func() {
+		output, err := analyzer.Analyze(deploymentWithPodSpec("      terminationGracePeriodSeconds: 0", `        lifecycle:
+          preStop:
+            exec:
+              command: ["sleep", "5"]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("terminationGracePeriodSeconds is 0"))
+	})
+})
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newerFields are pod template fields that are newer than the vendored API types,
+// so they are read from the unstructured object
+type newerFields struct {
+	// StartupProbes has names of containers that declare startup probe
+	StartupProbes map[string]bool
+	// TopologySpreadKeys has topology keys of topology spread constraints
+	TopologySpreadKeys []string
+}
+
+func parseNewerFields(fields map[string]interface{}) newerFields {
+	result := newerFields{StartupProbes: map[string]bool{}}
+	kind, _, _ := unstructured.NestedString(fields, "kind")
+	spec := podSpecPath(kind)
+
+	containers, _, _ := unstructured.NestedSlice(fields, append(spec, "containers")...)
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		if container["startupProbe"] != nil {
+			result.StartupProbes[name] = true
+		}
+	}
+
+	constraints, _, _ := unstructured.NestedSlice(fields, append(spec, "topologySpreadConstraints")...)
+	for _, c := range constraints {
+		constraint, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if key, ok, _ := unstructured.NestedString(constraint, "topologyKey"); ok {
+			result.TopologySpreadKeys = append(result.TopologySpreadKeys, key)
+		}
+	}
+	return result
+}
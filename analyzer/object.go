@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// SourceAnnotation is the file or template that produced the object. It is shown next to the object name
+const SourceAnnotation = "haornot/source"
+
+// Manifests without apiVersion and kind are treated as deployments
+const (
+	defaultAPIVersion = "apps/v1"
+	defaultKind       = "Deployment"
+)
+
+// deserializer is shared, because creating it on every call is slow for large inputs
+var deserializer = scheme.Codecs.UniversalDeserializer()
+
+// Decode decodes single YAML or JSON manifest. Fields that are newer than the vendored API types are kept,
+// and "# Source:" comment from helm template output is stored in SourceAnnotation.
+func Decode(manifest []byte) (*unstructured.Unstructured, error) {
+	data, err := yaml.ToJSON(manifest)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+
+	u := &unstructured.Unstructured{Object: fields}
+	if u.GetAPIVersion() == "" {
+		u.SetAPIVersion(defaultAPIVersion)
+	}
+	if u.GetKind() == "" {
+		u.SetKind(defaultKind)
+	}
+	if source := sourceOf(manifest); source != "" {
+		// Invalid metadata is reported when object is converted to typed API
+		unstructured.SetNestedField(u.Object, source, "metadata", "annotations", SourceAnnotation)
+	}
+	return u, nil
+}
+
+// unstructuredKinds are analyzed without typed API, because the vendored API types do not have them
+var unstructuredKinds = map[schema.GroupKind]bool{
+	priorityClassKind: true,
+}
+
+// object is a typed object with details that typed API does not have
+type object struct {
+	runtime.Object
+	gvk    schema.GroupVersionKind
+	source string
+	newer  newerFields
+	// fields is the object as it was passed, before conversion to typed API
+	fields map[string]interface{}
+}
+
+func newObject(obj runtime.Object) (*object, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		fields := u.Object
+		older, hasOlder := olderVersions[u.GroupVersionKind()]
+		if hasOlder {
+			fields = u.DeepCopy().Object
+			fields["apiVersion"] = older.String()
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		typed, gvk, err := deserializer.Decode(data, nil, nil)
+		if runtime.IsNotRegisteredError(err) && unstructuredKinds[u.GroupVersionKind().GroupKind()] {
+			return &object{Object: u, gvk: u.GroupVersionKind(), source: u.GetAnnotations()[SourceAnnotation], fields: u.Object}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hasOlder {
+			*gvk = u.GroupVersionKind()
+		}
+		return &object{Object: typed, gvk: *gvk, source: u.GetAnnotations()[SourceAnnotation], newer: parseNewerFields(u.Object), fields: u.Object}, nil
+	}
+
+	kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Typed objects usually do not have apiVersion and kind set
+	apiVersion, kind := kinds[0].ToAPIVersionAndKind()
+	fields["apiVersion"] = apiVersion
+	fields["kind"] = kind
+	return &object{Object: obj, gvk: kinds[0], source: accessor.GetAnnotations()[SourceAnnotation], newer: parseNewerFields(fields), fields: fields}, nil
+}
+
+func (o *object) name() string {
+	name, _, _ := unstructured.NestedString(o.fields, "metadata", "name")
+	return name
+}
+
+func (o *object) namespace() string {
+	namespace, _, _ := unstructured.NestedString(o.fields, "metadata", "namespace")
+	return namespace
+}
+
+// DecodeManifests decodes manifests in parallel. Results and errors are in the same order as manifests,
+// and the error is nil for every decoded manifest.
+func DecodeManifests(manifests [][]byte, workers int) ([]*unstructured.Unstructured, []error) {
+	objects := make([]*unstructured.Unstructured, len(manifests))
+	errors := make([]error, len(manifests))
+	parallel(len(manifests), workers, func(i int) {
+		objects[i], errors[i] = Decode(manifests[i])
+	})
+	return objects, errors
+}
+
+func decodeObject(manifest []byte) (*object, error) {
+	u, err := Decode(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return newObject(u)
+}
@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallel calls work for every index from 0 to n-1 on a pool of workers.
+// Every call writes only its own index, so results keep the input order.
+// Number of workers defaults to the number of CPUs.
+func parallel(n, workers int, work func(i int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
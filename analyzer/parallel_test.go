@@ -0,0 +1,43 @@
+package analyzer_test
+
+import (
+	"fmt"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Parallel analysis", func() {
+	It("keeps the order of objects", func() {
+		manifests := [][]byte{}
+		names := []string{}
+		for i := 0; i < 50; i++ {
+			name := fmt.Sprintf("app-%d", i)
+			names = append(names, name)
+			manifests = append(manifests, []byte(fmt.Sprintf(benchmarkDeployment, name, name, name, name)))
+		}
+
+		decoded, errors := analyzer.DecodeManifests(manifests, 8)
+		objects := []runtime.Object{}
+		for i := range decoded {
+			Expect(errors[i]).NotTo(HaveOccurred())
+			objects = append(objects, decoded[i])
+		}
+
+		result, err := analyzer.AnalyzeObjects(objects, analyzer.Options{Workers: 8})
+		Expect(err).NotTo(HaveOccurred())
+		analyzed := []string{}
+		for _, msg := range result.Workloads {
+			analyzed = append(analyzed, msg.Name)
+		}
+		Expect(analyzed).To(Equal(names))
+	})
+
+	It("returns decode errors in the order of manifests", func() {
+		_, errors := analyzer.DecodeManifests([][]byte{[]byte("kind: [")}, 4)
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0]).To(HaveOccurred())
+	})
+})
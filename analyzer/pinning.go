@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const nodeNameMessage = "Pods set nodeName %s. All replicas run on one node"
+const nodeSelectorMessage = "nodeSelector %s=%s pins all replicas to one %s"
+const nodeAffinityMessage = "Required node affinity pins all replicas to %s %s"
+
+const hostnameLabel = "kubernetes.io/hostname"
+
+// Node affinity can select nodes by name with matchFields
+const nodeNameField = "metadata.name"
+
+// analyzeNodePinning checks that scheduling constraints allow more than one node and zone
+func analyzeNodePinning(spec corev1.PodSpec) []string {
+	errors := []string{}
+	if spec.NodeName != "" {
+		errors = append(errors, fmt.Sprintf(nodeNameMessage, spec.NodeName))
+	}
+
+	keys := []string{}
+	for key := range spec.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if domain := pinnedDomain(key); domain != "" {
+			errors = append(errors, fmt.Sprintf(nodeSelectorMessage, key, spec.NodeSelector[key], domain))
+		}
+	}
+
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return errors
+	}
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for _, domain := range []string{"node", "zone"} {
+		if value, ok := pinnedByTerms(terms, domain); ok {
+			errors = append(errors, fmt.Sprintf(nodeAffinityMessage, domain, value))
+		}
+	}
+	return errors
+}
+
+// pinnedDomain returns node or zone for labels that have one value per node or zone
+func pinnedDomain(key string) string {
+	if key == hostnameLabel {
+		return "node"
+	}
+	if zoneTopologyKeys[key] {
+		return "zone"
+	}
+	return ""
+}
+
+// pinnedByTerms returns the only node or zone allowed by terms. Terms are ORed, so every term has to select the same one
+func pinnedByTerms(terms []corev1.NodeSelectorTerm, domain string) (string, bool) {
+	values := map[string]bool{}
+	for _, term := range terms {
+		value, ok := pinnedByTerm(term, domain)
+		if !ok {
+			return "", false
+		}
+		values[value] = true
+	}
+	if len(values) != 1 {
+		return "", false
+	}
+	for value := range values {
+		return value, true
+	}
+	return "", false
+}
+
+// pinnedByTerm returns the value when term requires exactly one node or zone. Requirements in term are ANDed
+func pinnedByTerm(term corev1.NodeSelectorTerm, domain string) (string, bool) {
+	requirements := term.MatchExpressions
+	if domain == "node" {
+		for _, field := range term.MatchFields {
+			if field.Key == nodeNameField {
+				requirements = append(requirements, corev1.NodeSelectorRequirement{Key: hostnameLabel, Operator: field.Operator, Values: field.Values})
+			}
+		}
+	}
+	for _, r := range requirements {
+		if pinnedDomain(r.Key) == domain && r.Operator == corev1.NodeSelectorOpIn && len(r.Values) == 1 {
+			return r.Values[0], true
+		}
+	}
+	return "", false
+}
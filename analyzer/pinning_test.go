@@ -0,0 +1,79 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze node pinning", func() {
+	It("returns message for nodeName", func() {
+		output, err := analyzer.Analyze(nginxManifest{podSpec: "      nodeName: node-1"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pods set nodeName node-1. All replicas run on one node"))
+	})
+
+	It("returns message for nodeSelector with hostname or zone", func() {
+		output, err := analyzer.Analyze(nginxManifest{podSpec: `      nodeSelector:
+        kubernetes.io/hostname: node-1
+        topology.kubernetes.io/zone: eu-west-1a
+        disktype: ssd`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("nodeSelector kubernetes.io/hostname=node-1 pins all replicas to one node"))
+		Expect(output).To(HaveMatchingElement("nodeSelector topology.kubernetes.io/zone=eu-west-1a pins all replicas to one zone"))
+		Expect(output).NotTo(HaveMatchingElement("disktype"))
+	})
+
+	It("returns message when every required affinity term selects the same node", func() {
+		output, err := analyzer.Analyze(nginxManifest{podSpec: `      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: kubernetes.io/hostname
+                operator: In
+                values: [node-1]
+            - matchFields:
+              - key: metadata.name
+                operator: In
+                values: [node-1]`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Required node affinity pins all replicas to node node-1"))
+	})
+
+	It("is successful when affinity allows several nodes or zones", func() {
+		output, err := analyzer.Analyze(nginxManifest{podSpec: `      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: topology.kubernetes.io/zone
+                operator: In
+                values: [eu-west-1a, eu-west-1b]
+            - matchExpressions:
+              - key: kubernetes.io/hostname
+                operator: In
+                values: [node-1]`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("pins"))
+	})
+
+	It("returns message for single zone affinity", func() {
+		output, err := analyzer.Analyze(nginxManifest{podSpec: `      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: failure-domain.beta.kubernetes.io/zone
+                operator: In
+                values: [eu-west-1a]`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Required node affinity pins all replicas to zone eu-west-1a"))
+	})
+
+	It("does not check DaemonSets", func() {
+		output, err := analyzer.Analyze(nginxManifest{kind: "DaemonSet", podSpec: "      nodeSelector:\n        kubernetes.io/hostname: node-1"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("pins"))
+	})
+})
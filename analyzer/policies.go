@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alex-slynko/haornot/types"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+)
+
+// Policies are Rego policies evaluated for every object.
+// Every package can define `deny` and `warn` rules with messages, same as in conftest. The object is their input.
+// `violation` rules of Gatekeeper policies are errors. Their input is the admission review of the object
+// in `input.review`, and `input.parameters` is empty, because constraints are not loaded.
+// Messages can be strings or objects with `msg` field. All objects from the same input are in `data.haornot.objects`.
+type Policies struct {
+	compiler *ast.Compiler
+	packages []string
+}
+
+// LoadPolicies loads all .rego files from directory and its subdirectories. Rego tests are skipped
+func LoadPolicies(dir string) (*Policies, error) {
+	modules := map[string]*ast.Module{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".rego" || strings.HasSuffix(path, "_test.rego") {
+			return nil
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		module, err := ast.ParseModule(path, string(contents))
+		if err != nil {
+			return err
+		}
+		if err := checkRules(module); err != nil {
+			return err
+		}
+		modules[path] = module
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no policies found in %s", dir)
+	}
+
+	compiler := ast.NewCompiler()
+	compiler.Compile(modules)
+	if compiler.Failed() {
+		return nil, compiler.Errors
+	}
+
+	packages := map[string]bool{}
+	for _, module := range modules {
+		packages[module.Package.Path.String()] = true
+	}
+	p := &Policies{compiler: compiler}
+	for pkg := range packages {
+		p.packages = append(p.packages, pkg)
+	}
+	sort.Strings(p.packages)
+	return p, nil
+}
+
+// policyRules are rules that are evaluated in every package
+var policyRules = []string{"deny", "warn", "violation"}
+
+// checkRules fails for policy rules that are not sets of messages, e.g. `deny = msg`, with their file and line
+func checkRules(module *ast.Module) error {
+	for _, rule := range module.Rules {
+		name := rule.Head.Name.String()
+		for _, policyRule := range policyRules {
+			if name == policyRule && rule.Head.DocKind() != ast.PartialSetDoc {
+				return fmt.Errorf("%s: %s must be a set of messages, e.g. %s[msg] { ... }", rule.Location, name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// policyFindings are deny and warn messages for one object
+type policyFindings struct {
+	// evaluated is false when no policies were loaded
+	evaluated bool
+	deny      []string
+	warn      []string
+}
+
+// evaluate returns findings in the same order as objects
+func (p *Policies) evaluate(objects []*object, workers int) ([]policyFindings, error) {
+	ctx := context.Background()
+	all := []interface{}{}
+	for _, o := range objects {
+		all = append(all, o.fields)
+	}
+	store := inmem.NewFromObject(map[string]interface{}{"haornot": map[string]interface{}{"objects": all}})
+
+	queries := map[string]rego.PreparedEvalQuery{}
+	for _, pkg := range p.packages {
+		for _, rule := range policyRules {
+			query, err := rego.New(rego.Query(pkg+"."+rule), rego.Compiler(p.compiler), rego.Store(store)).PrepareForEval(ctx)
+			if err != nil {
+				return nil, err
+			}
+			queries[pkg+"."+rule] = query
+		}
+	}
+
+	findings := make([]policyFindings, len(objects))
+	errors := make([]error, len(objects))
+	parallel(len(objects), workers, func(i int) {
+		findings[i].evaluated = true
+		for _, pkg := range p.packages {
+			deny, err := evaluatePolicy(ctx, queries[pkg+".deny"], objects[i].fields)
+			if err != nil {
+				errors[i] = err
+				return
+			}
+			warn, err := evaluatePolicy(ctx, queries[pkg+".warn"], objects[i].fields)
+			if err != nil {
+				errors[i] = err
+				return
+			}
+			violation, err := evaluatePolicy(ctx, queries[pkg+".violation"], gatekeeperInput(objects[i]))
+			if err != nil {
+				errors[i] = err
+				return
+			}
+			findings[i].deny = append(findings[i].deny, deny...)
+			findings[i].deny = append(findings[i].deny, violation...)
+			findings[i].warn = append(findings[i].warn, warn...)
+		}
+	})
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return findings, nil
+}
+
+// addTo adds denied messages to errors and warnings to warnings of msg.
+// Deny and warn rules count as error and warning checks in the score
+func (f policyFindings) addTo(msg *types.Message, card *scorecard) {
+	if !f.evaluated {
+		return
+	}
+	msg.Errors = append(msg.Errors, card.check(ErrorSeverity, f.deny)...)
+	msg.Warnings = append(msg.Warnings, card.check(WarningSeverity, f.warn)...)
+}
+
+// gatekeeperInput is the input of Gatekeeper policies, same as when the object is created
+func gatekeeperInput(o *object) map[string]interface{} {
+	return map[string]interface{}{
+		"review": map[string]interface{}{
+			"object":    o.fields,
+			"kind":      map[string]interface{}{"group": o.gvk.Group, "version": o.gvk.Version, "kind": o.gvk.Kind},
+			"name":      o.name(),
+			"namespace": o.namespace(),
+			"operation": "CREATE",
+		},
+		"parameters": map[string]interface{}{},
+	}
+}
+
+func evaluatePolicy(ctx context.Context, query rego.PreparedEvalQuery, input map[string]interface{}) ([]string, error) {
+	results, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, err
+	}
+	messages := []string{}
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s must be a set of messages", expression.Text)
+			}
+			for _, value := range values {
+				messages = append(messages, policyMessage(value))
+			}
+		}
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+func policyMessage(value interface{}) string {
+	if violation, ok := value.(map[string]interface{}); ok {
+		if msg, ok := violation["msg"].(string); ok {
+			return msg
+		}
+	}
+	if msg, ok := value.(string); ok {
+		return msg
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,145 @@
+package analyzer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Policies", func() {
+	var (
+		policies *analyzer.Policies
+		objects  []runtime.Object
+	)
+
+	BeforeEach(func() {
+		var err error
+		policies, err = analyzer.LoadPolicies(filepath.Join("..", "fixtures", "policies"))
+		Expect(err).NotTo(HaveOccurred())
+
+		objects = []runtime.Object{decode(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`)}
+	})
+
+	It("adds denied messages to errors", func() {
+		result, err := analyzer.AnalyzeObjects(objects, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).To(HaveMatchingElement("Deployment nginx does not have team label"))
+	})
+
+	It("adds Gatekeeper violations to errors", func() {
+		result, err := analyzer.AnalyzeObjects(objects, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).To(HaveMatchingElement("Deployment nginx must have owner annotation"))
+
+		objects = []runtime.Object{decode(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    owner: payments
+spec:
+  replicas: 3
+`)}
+		result, err = analyzer.AnalyzeObjects(objects, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("owner annotation"))
+	})
+
+	It("has all objects of the input in data", func() {
+		result, err := analyzer.AnalyzeObjects(objects, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).To(HaveMatchingElement("Deployment nginx is not covered by PodDisruptionBudget"))
+
+		objects = append(objects, decode(`apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: nginx
+spec:
+  minAvailable: 2
+  selector:
+    matchLabels:
+      app: nginx
+`))
+		result, err = analyzer.AnalyzeObjects(objects, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("PodDisruptionBudget"))
+	})
+
+	It("evaluates typed objects", func() {
+		replicas := int32(3)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{deployment}, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0]).NotTo(HaveMatchingElement("team label"))
+		Expect(result.Workloads[0]).To(HaveMatchingElement("Deployment web is not covered by PodDisruptionBudget"))
+	})
+
+	It("returns messages for other objects", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Labels: map[string]string{"team": "web"}}}
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{configMap}, analyzer.Options{Policies: policies})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Others).To(HaveLen(1))
+		Expect(result.Others[0].Kind).To(Equal("ConfigMap"))
+		Expect(result.Others[0].Warnings).To(ConsistOf("ConfigMap settings does not have app label"))
+		Expect(result.HasErrors()).To(BeFalse())
+	})
+
+	Context("LoadPolicies", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "haornot")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("returns error when there are no policies", func() {
+			_, err := analyzer.LoadPolicies(dir)
+			Expect(err).To(MatchError(ContainSubstring("no policies found")))
+		})
+
+		It("returns error for invalid policy", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "bad.rego"), []byte("package bad\n\ndeny[msg] {\n"), 0644)).To(Succeed())
+			_, err := analyzer.LoadPolicies(dir)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns error with file name for rules that are not sets", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "complete.rego"), []byte("package complete\n\ndeny = \"always\"\n"), 0644)).To(Succeed())
+			_, err := analyzer.LoadPolicies(dir)
+			Expect(err).To(MatchError(filepath.Join(dir, "complete.rego") + ":3: deny must be a set of messages, e.g. deny[msg] { ... }"))
+		})
+	})
+})
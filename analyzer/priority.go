@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const priorityClassMissingMessage = "Pods do not have priorityClassName. They are preempted first when the cluster runs out of resources"
+const priorityClassUndefinedMessage = "PriorityClass %s is not defined in the input. Pods are not created when it does not exist in the cluster"
+const priorityClassValueMessage = "PriorityClass %s has value %d, lower than required %d"
+const preemptionNeverMessage = "PriorityClass %s has preemptionPolicy Never. Pods wait for resources instead of preempting lower priority pods"
+
+var priorityClassKind = schema.GroupKind{Group: "scheduling.k8s.io", Kind: "PriorityClass"}
+
+// Kubernetes creates these priority classes in every cluster
+var systemPriorityClasses = map[string]bool{
+	"system-cluster-critical": true,
+	"system-node-critical":    true,
+}
+
+type priorityClass struct {
+	value            int64
+	preemptionPolicy string
+}
+
+// priorityClassIndex has PriorityClasses from the input by name
+type priorityClassIndex map[string]priorityClass
+
+// newPriorityClassIndex reads fields of PriorityClasses, because they are newer than the vendored API types
+func newPriorityClassIndex(objects []*object) priorityClassIndex {
+	classes := priorityClassIndex{}
+	for _, o := range objects {
+		if o.gvk.GroupKind() != priorityClassKind {
+			continue
+		}
+		value, _, _ := unstructured.NestedFieldNoCopy(o.fields, "value")
+		policy, _, _ := unstructured.NestedString(o.fields, "preemptionPolicy")
+		classes[o.name()] = priorityClass{value: toInt64(value), preemptionPolicy: policy}
+	}
+	return classes
+}
+
+// JSON numbers are decoded as int64 or float64
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// analyzePriorityClass checks that selected workloads have a priority class that preempts other pods
+func analyzePriorityClass(w *workload, classes priorityClassIndex, selector string, minPriority int32) []string {
+	name := w.Template.Spec.PriorityClassName
+	if name == "" {
+		// Selector is validated with config
+		parsed, err := labels.Parse(selector)
+		if err != nil || !parsed.Matches(labels.Set(w.Labels)) {
+			return nil
+		}
+		return []string{priorityClassMissingMessage}
+	}
+	if systemPriorityClasses[name] {
+		return nil
+	}
+
+	class, ok := classes[name]
+	if !ok {
+		return []string{fmt.Sprintf(priorityClassUndefinedMessage, name)}
+	}
+	errors := []string{}
+	if class.value < int64(minPriority) {
+		errors = append(errors, fmt.Sprintf(priorityClassValueMessage, name, class.value, minPriority))
+	}
+	if class.preemptionPolicy == "Never" {
+		errors = append(errors, fmt.Sprintf(preemptionNeverMessage, name))
+	}
+	return errors
+}
@@ -0,0 +1,78 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Analyze priority class", func() {
+	var options analyzer.Options
+
+	priorityClass := func(value, preemptionPolicy string) runtime.Object {
+		return decode(`apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: high
+value: ` + value + `
+preemptionPolicy: ` + preemptionPolicy + `
+`)
+	}
+	deployment := func(tier, priorityClassName string) runtime.Object {
+		return decode(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  labels:
+    tier: ` + tier + `
+spec:
+  replicas: 3
+  template:
+    spec:
+      priorityClassName: ` + priorityClassName + `
+      containers:
+      - name: api
+        image: api:1.0
+`)
+	}
+
+	BeforeEach(func() {
+		options = analyzer.Options{
+			Config: analyzer.Config{Profile: analyzer.Profile{PriorityClassSelector: "tier=critical", MinPriority: 1000}},
+			Rules:  map[string]bool{analyzer.PriorityClassRule: true},
+		}
+	})
+
+	It("requires priorityClassName for selected workloads", func() {
+		Expect(workloadErrors(options, deployment("critical", `""`))).To(ContainElement(
+			"Pods do not have priorityClassName. They are preempted first when the cluster runs out of resources"))
+		Expect(workloadErrors(options, deployment("batch", `""`))).NotTo(ContainElement(ContainSubstring("priorityClassName")))
+	})
+
+	It("checks value and preemption policy of priority class from the input", func() {
+		Expect(workloadErrors(options, priorityClass("100", "Never"), deployment("critical", "high"))).To(ContainElement("PriorityClass high has value 100, lower than required 1000"))
+		Expect(workloadErrors(options, priorityClass("100", "Never"), deployment("critical", "high"))).To(ContainElement(
+			"PriorityClass high has preemptionPolicy Never. Pods wait for resources instead of preempting lower priority pods"))
+		Expect(workloadErrors(options, priorityClass("1000000", "PreemptLowerPriority"), deployment("critical", "high"))).NotTo(ContainElement(ContainSubstring("PriorityClass")))
+	})
+
+	It("returns message for undefined priority class", func() {
+		Expect(workloadErrors(options, deployment("batch", "high"))).To(ContainElement(
+			"PriorityClass high is not defined in the input. Pods are not created when it does not exist in the cluster"))
+	})
+
+	It("allows system priority classes", func() {
+		Expect(workloadErrors(options, deployment("critical", "system-cluster-critical"))).NotTo(ContainElement(ContainSubstring("PriorityClass")))
+	})
+
+	It("is disabled by default", func() {
+		options.Rules = nil
+		Expect(workloadErrors(options, deployment("critical", `""`))).NotTo(ContainElement(ContainSubstring("priorityClassName")))
+	})
+
+	It("fails for invalid selector", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{PriorityClassSelector: "tier in critical"}}
+		Expect(config.Validate()).To(MatchError(ContainSubstring("priorityClassSelector is invalid")))
+	})
+})
@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const identicalProbesMessage = "Liveness and readiness probes for pod %s are identical. Failing readiness will also restart the container"
+const aggressiveLivenessMessage = "Liveness probe for pod %s restarts container after %d failed checks within %d seconds"
+const probeTimeoutMessage = "%s probe for pod %s has timeoutSeconds %d larger than periodSeconds %d"
+const probePortMessage = "%s probe for pod %s uses port %s that is not declared by the container"
+const startupProbeMissingMessage = "Pod %s waits %d seconds before probing but does not have startup probe"
+
+// Defaults applied by Kubernetes when probe fields are omitted
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbeFailureThreshold = 3
+)
+
+const minLivenessFailureSeconds = 10
+const slowStartSeconds = 30
+
+func analyzeProbes(c corev1.Container, hasStartupProbe bool) []string {
+	errors := []string{}
+
+	if c.LivenessProbe != nil && c.ReadinessProbe != nil && equality.Semantic.DeepEqual(c.LivenessProbe, c.ReadinessProbe) {
+		errors = append(errors, fmt.Sprintf(identicalProbesMessage, c.Name))
+	}
+
+	if c.LivenessProbe != nil {
+		failures := valueOrDefault(c.LivenessProbe.FailureThreshold, defaultProbeFailureThreshold)
+		period := valueOrDefault(c.LivenessProbe.PeriodSeconds, defaultProbePeriodSeconds)
+		if failures == 1 || failures*period < minLivenessFailureSeconds {
+			errors = append(errors, fmt.Sprintf(aggressiveLivenessMessage, c.Name, failures, failures*period))
+		}
+	}
+
+	for _, probe := range []struct {
+		kind  string
+		probe *corev1.Probe
+	}{{"Liveness", c.LivenessProbe}, {"Readiness", c.ReadinessProbe}} {
+		if probe.probe == nil {
+			continue
+		}
+		timeout := valueOrDefault(probe.probe.TimeoutSeconds, defaultProbeTimeoutSeconds)
+		period := valueOrDefault(probe.probe.PeriodSeconds, defaultProbePeriodSeconds)
+		if timeout > period {
+			errors = append(errors, fmt.Sprintf(probeTimeoutMessage, probe.kind, c.Name, timeout, period))
+		}
+
+		if port := probePort(probe.probe); port != nil && !hasContainerPort(c, *port) {
+			errors = append(errors, fmt.Sprintf(probePortMessage, probe.kind, c.Name, port.String()))
+		}
+	}
+
+	if !hasStartupProbe {
+		delay := int32(0)
+		for _, probe := range []*corev1.Probe{c.LivenessProbe, c.ReadinessProbe} {
+			if probe != nil && probe.InitialDelaySeconds > delay {
+				delay = probe.InitialDelaySeconds
+			}
+		}
+		if delay >= slowStartSeconds {
+			errors = append(errors, fmt.Sprintf(startupProbeMissingMessage, c.Name, delay))
+		}
+	}
+
+	return errors
+}
+
+func probePort(probe *corev1.Probe) *intstr.IntOrString {
+	if probe.HTTPGet != nil {
+		return &probe.HTTPGet.Port
+	}
+	if probe.TCPSocket != nil {
+		return &probe.TCPSocket.Port
+	}
+	return nil
+}
+
+func hasContainerPort(c corev1.Container, port intstr.IntOrString) bool {
+	if port.Type == intstr.String {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Ports are informational, so numeric probe ports are only checked when some are declared
+	if len(c.Ports) == 0 {
+		return true
+	}
+	for _, p := range c.Ports {
+		if p.ContainerPort == port.IntVal {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOrDefault(value, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,142 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze probes", func() {
+	deploymentWithContainer := func(container string) []byte {
+		return nginxManifest{spec: "  minReadySeconds: 10", container: `        ports:
+        - name: http
+          containerPort: 80
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 64Mi
+        lifecycle:
+          preStop:
+            exec:
+              command: ["sleep", "5"]
+` + container}.yaml()
+	}
+
+	It("is successful for well configured probes", func() {
+		output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          httpGet:
+            path: /
+            port: http
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          periodSeconds: 20
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(BeEmpty())
+	})
+
+	It("returns message when liveness and readiness probes are identical", func() {
+		output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("identical"))
+	})
+
+	It("returns message for aggressive liveness probe", func() {
+		output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          tcpSocket:
+            port: 80
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          failureThreshold: 1
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("after 1 failed checks"))
+
+		output, err = analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          tcpSocket:
+            port: 80
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          periodSeconds: 2
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("within 6 seconds"))
+	})
+
+	It("returns message when timeout is larger than period", func() {
+		output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+          timeoutSeconds: 5
+          periodSeconds: 3
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("timeoutSeconds 5"))
+	})
+
+	It("returns message when probe port is not declared", func() {
+		output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          httpGet:
+            path: /
+            port: 8080
+        livenessProbe:
+          tcpSocket:
+            port: metrics
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Readiness probe for pod nginx uses port 8080"))
+		Expect(output).To(HaveMatchingElement("Liveness probe for pod nginx uses port metrics"))
+	})
+
+	Context("slow starting containers", func() {
+		It("returns message when startup probe is missing", func() {
+			output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          tcpSocket:
+            port: 80
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          initialDelaySeconds: 120
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HaveMatchingElement("startup probe"))
+		})
+
+		It("is successful when startup probe is present", func() {
+			output, err := analyzer.Analyze(deploymentWithContainer(`        readinessProbe:
+          tcpSocket:
+            port: 80
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 80
+          initialDelaySeconds: 120
+        startupProbe:
+          httpGet:
+            path: /
+            port: 80
+          failureThreshold: 30
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).NotTo(HaveMatchingElement("startup probe"))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const resourceRequestMissingMessage = "Pod %s does not request %s. It will be evicted first under node pressure"
+const memoryLimitMissingMessage = "Pod %s does not have memory limit"
+const limitLowerThanRequestMessage = "Pod %s has %s limit %s lower than request %s"
+const notGuaranteedQoSMessage = "Pod %s is not in Guaranteed QoS class. Set cpu and memory limits equal to requests"
+
+var guaranteedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+func analyzeResources(spec corev1.PodSpec, requestsAndLimits, guaranteedQoS bool) []string {
+	errors := []string{}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		if guaranteedQoS && !isGuaranteed(c.Resources) {
+			errors = append(errors, fmt.Sprintf(notGuaranteedQoSMessage, c.Name))
+		}
+		if !requestsAndLimits {
+			continue
+		}
+
+		requests := c.Resources.Requests
+		limits := c.Resources.Limits
+
+		for _, name := range guaranteedResources {
+			if _, ok := requests[name]; !ok {
+				// Kubernetes uses the limit as request when only limit is set
+				if _, ok := limits[name]; !ok {
+					errors = append(errors, fmt.Sprintf(resourceRequestMissingMessage, c.Name, name))
+				}
+			}
+		}
+
+		if _, ok := limits[corev1.ResourceMemory]; !ok {
+			errors = append(errors, fmt.Sprintf(memoryLimitMissingMessage, c.Name))
+		}
+
+		for _, name := range sortedResourceNames(requests) {
+			request := requests[name]
+			limit, ok := limits[name]
+			if ok && limit.Cmp(request) < 0 {
+				errors = append(errors, fmt.Sprintf(limitLowerThanRequestMessage, c.Name, name, limit.String(), request.String()))
+			}
+		}
+
+	}
+	return errors
+}
+
+func isGuaranteed(resources corev1.ResourceRequirements) bool {
+	for _, name := range guaranteedResources {
+		limit, ok := resources.Limits[name]
+		if !ok {
+			return false
+		}
+		request, ok := resources.Requests[name]
+		if ok && request.Cmp(limit) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := []corev1.ResourceName{}
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,119 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze resources", func() {
+	deploymentWithResources := func(resources string) []byte {
+		return nginxManifest{spec: "  minReadySeconds: 10", podSpec: `      initContainers:
+      - name: migrate
+        image: migrate:1.0
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            cpu: 100m
+            memory: 64Mi`, container: `        readinessProbe:
+          tcpSocket:
+            port: 80
+` + resources}.yaml()
+	}
+
+	It("is successful when requests and limits are set", func() {
+		output, err := analyzer.Analyze(deploymentWithResources(`        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 128Mi
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(BeEmpty())
+	})
+
+	It("returns message when requests are missing", func() {
+		output, err := analyzer.Analyze(deploymentWithResources(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pod nginx does not request cpu"))
+		Expect(output).To(HaveMatchingElement("Pod nginx does not request memory"))
+		Expect(output).To(HaveMatchingElement("Pod nginx does not have memory limit"))
+	})
+
+	It("treats limits as requests when only limits are set", func() {
+		output, err := analyzer.Analyze(deploymentWithResources(`        resources:
+          limits:
+            cpu: 100m
+            memory: 64Mi
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("does not request"))
+	})
+
+	It("returns message when limit is lower than request", func() {
+		output, err := analyzer.Analyze(deploymentWithResources(`        resources:
+          requests:
+            cpu: "1"
+            memory: 1Gi
+          limits:
+            cpu: 500m
+            memory: 1Gi
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("cpu limit 500m lower than request 1"))
+		Expect(output).NotTo(HaveMatchingElement("memory limit"))
+	})
+
+	It("checks init containers", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate:1.0
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Pod migrate does not request cpu"))
+	})
+
+	Context("when Guaranteed QoS is required", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{GuaranteedQoS: true}}
+
+		It("returns message when requests differ from limits", func() {
+			output, err := analyzer.AnalyzeWithConfig(deploymentWithResources(`        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 128Mi
+`), config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HaveMatchingElement("Pod nginx is not in Guaranteed QoS class"))
+			Expect(output).NotTo(HaveMatchingElement("Pod migrate"))
+		})
+
+		It("is successful when limits equal requests", func() {
+			output, err := analyzer.AnalyzeWithConfig(deploymentWithResources(`        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            cpu: 100m
+            memory: 64Mi
+`), config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Errors).To(BeEmpty())
+		})
+	})
+})
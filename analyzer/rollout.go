@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/api/apps/v1"
+)
+
+const minReadySecondsMessage = "minReadySeconds is %d, lower than required %d. Pods that fail soon after start are counted as available and rollout continues"
+const revisionHistoryLimitMessage = "revisionHistoryLimit is %d, lower than required %d. Deployment can not be rolled back to older revisions"
+const progressDeadlineMessage = "progressDeadlineSeconds is %d, higher than allowed %d. Stuck rollouts are reported late"
+const noProgressDeadlineMessage = "Deployment does not have progress deadline. Stuck rollouts are never reported"
+const progressDeadlineTooShortMessage = "progressDeadlineSeconds %d must be greater than minReadySeconds %d"
+
+// Kubernetes uses these values when fields are omitted
+const (
+	defaultRevisionHistoryLimit    = 10
+	defaultProgressDeadlineSeconds = 600
+)
+
+// Thresholds used when profile does not set them
+const (
+	defaultMinReadySeconds            = 5
+	defaultMinRevisionHistoryLimit    = 1
+	defaultMaxProgressDeadlineSeconds = 600
+)
+
+// analyzeRollout checks that rollout stops on broken pods and can be rolled back
+func analyzeRollout(spec v1.DeploymentSpec, profile Profile) []string {
+	errors := []string{}
+	if spec.MinReadySeconds < profile.minReadySeconds() {
+		errors = append(errors, fmt.Sprintf(minReadySecondsMessage, spec.MinReadySeconds, profile.minReadySeconds()))
+	}
+
+	revisionHistoryLimit := int32(defaultRevisionHistoryLimit)
+	if spec.RevisionHistoryLimit != nil {
+		revisionHistoryLimit = *spec.RevisionHistoryLimit
+	}
+	if revisionHistoryLimit < profile.minRevisionHistoryLimit() {
+		errors = append(errors, fmt.Sprintf(revisionHistoryLimitMessage, revisionHistoryLimit, profile.minRevisionHistoryLimit()))
+	}
+
+	progressDeadline := int32(defaultProgressDeadlineSeconds)
+	if spec.ProgressDeadlineSeconds != nil {
+		progressDeadline = *spec.ProgressDeadlineSeconds
+	}
+	switch {
+	case progressDeadline == math.MaxInt32:
+		// Legacy Deployments without progressDeadlineSeconds
+		errors = append(errors, noProgressDeadlineMessage)
+	case progressDeadline > profile.maxProgressDeadlineSeconds():
+		errors = append(errors, fmt.Sprintf(progressDeadlineMessage, progressDeadline, profile.maxProgressDeadlineSeconds()))
+	}
+	if progressDeadline <= spec.MinReadySeconds {
+		errors = append(errors, fmt.Sprintf(progressDeadlineTooShortMessage, progressDeadline, spec.MinReadySeconds))
+	}
+	return errors
+}
@@ -0,0 +1,105 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze rollout", func() {
+	It("returns message for minReadySeconds 0 with default config", func() {
+		output, err := analyzer.Analyze(nginxManifest{}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("minReadySeconds is 0, lower than required 5. Pods that fail soon after start are counted as available and rollout continues"))
+		Expect(output).NotTo(HaveMatchingElement("progressDeadlineSeconds"))
+		Expect(output).NotTo(HaveMatchingElement("revisionHistoryLimit"))
+
+		output, err = analyzer.Analyze(nginxManifest{spec: "  minReadySeconds: 5"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("Seconds"))
+	})
+
+	It("does not require minReadySeconds when threshold is set to 0", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinReadySeconds: threshold(10)}, Profiles: map[string]analyzer.Profile{
+			"dev": {MinReadySeconds: threshold(0)},
+		}}
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("minReadySeconds is 0, lower than required 10"))
+
+		config.DefaultProfile = "dev"
+		output, err = analyzer.AnalyzeWithConfig(nginxManifest{}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("minReadySeconds"))
+	})
+
+	It("returns message when revision history is removed", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: "  revisionHistoryLimit: 0"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("revisionHistoryLimit is 0, lower than required 1. Deployment can not be rolled back to older revisions"))
+	})
+
+	It("returns message when progress deadline is too long or shorter than minReadySeconds", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: "  progressDeadlineSeconds: 3600"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("progressDeadlineSeconds is 3600, higher than allowed 600. Stuck rollouts are reported late"))
+
+		output, err = analyzer.Analyze(nginxManifest{spec: "  progressDeadlineSeconds: 30\n  minReadySeconds: 30"}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("progressDeadlineSeconds 30 must be greater than minReadySeconds 30"))
+	})
+
+	It("uses thresholds from config", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinReadySeconds: threshold(10), MinRevisionHistoryLimit: threshold(5), MaxProgressDeadlineSeconds: threshold(300)}}
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{spec: "  revisionHistoryLimit: 3"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("minReadySeconds is 0, lower than required 10"))
+		Expect(output).To(HaveMatchingElement("revisionHistoryLimit is 3, lower than required 5"))
+		Expect(output).To(HaveMatchingElement("progressDeadlineSeconds is 600, higher than allowed 300"))
+	})
+
+	It("uses defaults of legacy Deployments", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinRevisionHistoryLimit: threshold(3)}}
+
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{apiVersion: "extensions/v1beta1", spec: "  minReadySeconds: 10"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Deployment does not have progress deadline. Stuck rollouts are never reported"))
+		Expect(output).NotTo(HaveMatchingElement("revisionHistoryLimit"))
+		Expect(output).NotTo(HaveMatchingElement("higher than allowed"))
+
+		output, err = analyzer.AnalyzeWithConfig(nginxManifest{apiVersion: "apps/v1beta1", spec: "  minReadySeconds: 10"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("revisionHistoryLimit is 2, lower than required 3"))
+		Expect(output).NotTo(HaveMatchingElement("progress"))
+	})
+
+	It("does not check StatefulSets", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 3
+  revisionHistoryLimit: 0
+  template:
+    spec:
+      containers:
+      - name: db
+        image: db:1.0
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("revisionHistoryLimit"))
+	})
+
+	It("can allow removed history with 0 threshold", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinRevisionHistoryLimit: threshold(0)}}
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{spec: "  revisionHistoryLimit: 0"}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("revisionHistoryLimit"))
+	})
+
+	It("fails for negative thresholds", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinReadySeconds: threshold(-1)}}
+		Expect(config.Validate()).To(MatchError("rollout thresholds can not be negative"))
+	})
+})
@@ -0,0 +1,56 @@
+package analyzer
+
+// severityWeights are how much checks of every severity contribute to the score
+var severityWeights = map[string]int{
+	CriticalSeverity: 5,
+	ErrorSeverity:    3,
+	WarningSeverity:  1,
+}
+
+// ruleSeverities are severities of built-in rules
+var ruleSeverities = map[string]string{
+	ReplicasRule:         CriticalSeverity,
+	StrategyRule:         ErrorSeverity,
+	ReadinessProbeRule:   CriticalSeverity,
+	ProbesRule:           ErrorSeverity,
+	ImageVersionRule:     WarningSeverity,
+	ResourcesRule:        ErrorSeverity,
+	GuaranteedQoSRule:    WarningSeverity,
+	GracefulShutdownRule: ErrorSeverity,
+	ZoneSpreadRule:       ErrorSeverity,
+	StorageRule:          ErrorSeverity,
+	NodePinningRule:      ErrorSeverity,
+	HostPortsRule:        ErrorSeverity,
+	PriorityClassRule:    ErrorSeverity,
+	RolloutRule:          ErrorSeverity,
+	JobRule:              ErrorSeverity,
+	CronJobRule:          ErrorSeverity,
+	ControllerRule:       CriticalSeverity,
+}
+
+// Services that do not route traffic to ready pods cause downtime
+const serviceSeverity = CriticalSeverity
+
+// scorecard sums weights of all checks and of checks that passed
+type scorecard struct {
+	total  int
+	passed int
+}
+
+// check records the result of a check that passed when it returned no messages
+func (s *scorecard) check(severity string, messages []string) []string {
+	weight := severityWeights[severity]
+	s.total += weight
+	if len(messages) == 0 {
+		s.passed += weight
+	}
+	return messages
+}
+
+// score is the weighted share of passed checks from 0 to 100
+func (s scorecard) score() int {
+	if s.total == 0 {
+		return 100
+	}
+	return s.passed * 100 / s.total
+}
@@ -0,0 +1,134 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Score", func() {
+	goodDeployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  minReadySeconds: 10
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+        readinessProbe:
+          tcpSocket:
+            port: 80
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 64Mi
+`
+
+	It("is 100 when all checks pass", func() {
+		output, err := analyzer.Analyze([]byte(goodDeployment))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(BeEmpty())
+		Expect(output.Score).To(Equal(100))
+	})
+
+	It("is 0 when there are not enough replicas", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 1
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Score).To(Equal(0))
+	})
+
+	It("is lowered more by critical checks than by warnings", func() {
+		withoutReadiness, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 64Mi
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		withoutVersion, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+        readinessProbe:
+          tcpSocket:
+            port: 80
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            memory: 64Mi
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(withoutReadiness.Score).To(BeNumerically("<", withoutVersion.Score))
+		Expect(withoutVersion.Score).To(BeNumerically("<", 100))
+	})
+
+	It("counts warnings of custom rules", func() {
+		config := analyzer.Config{CustomRules: []analyzer.CustomRule{{
+			Name:       "teamLabel",
+			Expression: "has(object.metadata.labels)",
+			Message:    "no labels",
+			Severity:   analyzer.WarningSeverity,
+		}}}
+		output, err := analyzer.AnalyzeWithConfig([]byte(goodDeployment), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Errors).To(BeEmpty())
+		Expect(output.Score).To(BeNumerically("<", 100))
+	})
+
+	It("is the average of all messages for the whole input", func() {
+		good := decode(goodDeployment)
+		bad := decode("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: bad\nspec:\n  replicas: 1\n")
+
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{good, bad}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Score()).To(Equal(50))
+	})
+
+	It("counts objects that can not be analyzed as 0", func() {
+		good := decode(goodDeployment)
+		unknown := decode("apiVersion: example.com/v1\nkind: Widget\n")
+
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{good, unknown}, analyzer.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Score()).To(Equal(50))
+	})
+})
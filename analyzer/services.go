@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alex-slynko/haornot/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const serviceSelectsNothingMessage = "Service selector %s does not match any workload"
+const serviceTargetPortMessage = "Service port %s targets %s that is not declared by %s %s"
+const serviceWithoutReadinessMessage = "Service sends traffic to pod %s of %s %s that does not have readiness probe"
+
+// AnalyzeServices correlates Services with workloads from the same input
+func AnalyzeServices(manifests [][]byte) []*types.Message {
+	objects := []*object{}
+	for _, manifest := range manifests {
+		if o, err := decodeObject(manifest); err == nil {
+			objects = append(objects, o)
+		}
+	}
+	return analyzeServices(objects, nil, make([]policyFindings, len(objects)))
+}
+
+// analyzeServices returns a message for every Service. Workloads are parsed from objects unless they are passed.
+// Findings are in the same order as objects
+func analyzeServices(objects []*object, workloads []*workload, findings []policyFindings) []*types.Message {
+	if workloads == nil {
+		for _, o := range objects {
+			if w, err := newWorkload(o); err == nil {
+				workloads = append(workloads, w)
+			}
+		}
+	}
+
+	index := newWorkloadIndex(workloads)
+	messages := []*types.Message{}
+	for i, o := range objects {
+		service, ok := o.Object.(*corev1.Service)
+		if !ok {
+			continue
+		}
+		msg := analyzeService(service, index)
+		msg.Source = o.source
+		card := &scorecard{}
+		card.check(serviceSeverity, msg.Errors)
+		findings[i].addTo(msg, card)
+		msg.Score = card.score()
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// workloadIndex finds workloads by namespace and pod label, so large inputs do not match every service with every workload
+type workloadIndex map[string]map[string][]*workload
+
+func newWorkloadIndex(workloads []*workload) workloadIndex {
+	index := workloadIndex{}
+	for _, w := range workloads {
+		if index[w.Namespace] == nil {
+			index[w.Namespace] = map[string][]*workload{}
+		}
+		for key, value := range w.Template.Labels {
+			label := key + "=" + value
+			index[w.Namespace][label] = append(index[w.Namespace][label], w)
+		}
+	}
+	return index
+}
+
+// candidates returns workloads in the same namespace that have one of the selector labels, in the input order
+func (index workloadIndex) candidates(namespace string, selector map[string]string) []*workload {
+	for key, value := range selector {
+		return index[namespace][key+"="+value]
+	}
+	return nil
+}
+
+func analyzeService(service *corev1.Service, index workloadIndex) *types.Message {
+	msg := &types.Message{Kind: "Service", Name: service.Name, Namespace: service.Namespace, Errors: []string{}}
+	// Services without selector use manually managed endpoints
+	if len(service.Spec.Selector) == 0 {
+		return msg
+	}
+
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	matched := []*workload{}
+	for _, w := range index.candidates(service.Namespace, service.Spec.Selector) {
+		if selector.Matches(labels.Set(w.Template.Labels)) {
+			matched = append(matched, w)
+		}
+	}
+	if len(matched) == 0 {
+		msg.Errors = append(msg.Errors, fmt.Sprintf(serviceSelectsNothingMessage, selector.String()))
+		return msg
+	}
+
+	for _, w := range matched {
+		containers := w.Template.Spec.Containers
+		for _, port := range service.Spec.Ports {
+			target := port.TargetPort
+			if target.Type == intstr.Int && target.IntVal == 0 {
+				target = intstr.FromInt(int(port.Port))
+			}
+			if !anyContainerHasPort(containers, target) {
+				msg.Errors = append(msg.Errors, fmt.Sprintf(serviceTargetPortMessage, servicePortName(port), target.String(), strings.ToLower(w.Kind), w.Name))
+			}
+		}
+
+		for _, c := range containers {
+			if c.ReadinessProbe == nil {
+				msg.Errors = append(msg.Errors, fmt.Sprintf(serviceWithoutReadinessMessage, c.Name, strings.ToLower(w.Kind), w.Name))
+			}
+		}
+	}
+	return msg
+}
+
+func anyContainerHasPort(containers []corev1.Container, port intstr.IntOrString) bool {
+	declared := false
+	for _, c := range containers {
+		if len(c.Ports) == 0 {
+			continue
+		}
+		declared = true
+		if hasContainerPort(c, port) {
+			return true
+		}
+	}
+	// Ports are informational, so numeric target ports are only checked when some are declared
+	return !declared && port.Type == intstr.Int
+}
+
+func servicePortName(port corev1.ServicePort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%d", port.Port)
+}
@@ -0,0 +1,135 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AnalyzeServices", func() {
+	var deployment []byte
+
+	BeforeEach(func() {
+		deployment = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+        ports:
+        - name: http
+          containerPort: 80
+        readinessProbe:
+          tcpSocket:
+            port: 80
+`)
+	})
+
+	service := func(spec string) []byte {
+		return []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+spec:
+` + spec)
+	}
+
+	It("is successful when service matches deployment", func() {
+		messages := analyzer.AnalyzeServices([][]byte{service(`  selector:
+    app: nginx
+  ports:
+  - port: 80
+  - port: 8080
+    targetPort: http
+`), deployment})
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].Kind).To(Equal("Service"))
+		Expect(messages[0].Name).To(Equal("nginx"))
+		Expect(messages[0].Errors).To(BeEmpty())
+	})
+
+	It("ignores manifests that are not services", func() {
+		Expect(analyzer.AnalyzeServices([][]byte{deployment})).To(BeEmpty())
+	})
+
+	It("returns message when selector does not match any workload", func() {
+		messages := analyzer.AnalyzeServices([][]byte{service(`  selector:
+    app: web
+  ports:
+  - port: 80
+`), deployment})
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0]).To(HaveMatchingElement("app=web does not match any workload"))
+	})
+
+	It("does not match deployments from other namespaces", func() {
+		namespaced := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: other
+spec:
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`)
+		messages := analyzer.AnalyzeServices([][]byte{service(`  selector:
+    app: nginx
+  ports:
+  - port: 80
+`), namespaced})
+		Expect(messages[0]).To(HaveMatchingElement("does not match any workload"))
+	})
+
+	It("returns message when target port is not declared", func() {
+		messages := analyzer.AnalyzeServices([][]byte{service(`  selector:
+    app: nginx
+  ports:
+  - name: web
+    port: 80
+    targetPort: 8080
+  - name: metrics
+    port: 9090
+    targetPort: metrics
+`), deployment})
+		Expect(messages[0]).To(HaveMatchingElement("Service port web targets 8080"))
+		Expect(messages[0]).To(HaveMatchingElement("Service port metrics targets metrics"))
+	})
+
+	It("returns message when exposed deployment does not have readiness probe", func() {
+		withoutProbe := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`)
+		messages := analyzer.AnalyzeServices([][]byte{service(`  selector:
+    app: nginx
+  ports:
+  - port: 80
+`), withoutProbe})
+		Expect(messages[0]).To(HaveMatchingElement("pod nginx of deployment nginx that does not have readiness probe"))
+		Expect(messages[0]).NotTo(HaveMatchingElement("targets"))
+	})
+})
@@ -0,0 +1,14 @@
+package analyzer
+
+import "regexp"
+
+// helm template marks every document with the template that produced it
+var sourceComment = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+
+func sourceOf(manifest []byte) string {
+	match := sourceComment.FindSubmatch(manifest)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
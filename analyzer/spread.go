@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const zoneSpreadMessage = "Pods are not spread across zones. Add pod anti-affinity or topology spread constraint with zone topology key"
+
+var zoneTopologyKeys = map[string]bool{
+	"topology.kubernetes.io/zone":            true,
+	"failure-domain.beta.kubernetes.io/zone": true,
+}
+
+func analyzeZoneSpread(spec corev1.PodSpec, topologySpreadKeys []string) []string {
+	for _, key := range topologySpreadKeys {
+		if zoneTopologyKeys[key] {
+			return nil
+		}
+	}
+
+	if spec.Affinity != nil && spec.Affinity.PodAntiAffinity != nil {
+		antiAffinity := spec.Affinity.PodAntiAffinity
+		for _, term := range antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if zoneTopologyKeys[term.TopologyKey] {
+				return nil
+			}
+		}
+		for _, term := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if zoneTopologyKeys[term.PodAffinityTerm.TopologyKey] {
+				return nil
+			}
+		}
+	}
+	return []string{zoneSpreadMessage}
+}
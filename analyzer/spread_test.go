@@ -0,0 +1,64 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze zone spread", func() {
+	config := analyzer.Config{Profile: analyzer.Profile{Rules: map[string]bool{"zoneSpread": true}}}
+
+	It("returns message when pods are not spread across zones", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("not spread across zones"))
+	})
+
+	It("is not checked by default", func() {
+		output, err := analyzer.Analyze(nginxManifest{}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("spread across zones"))
+	})
+
+	It("accepts topology spread constraint", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{podSpec: `      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: topology.kubernetes.io/zone
+        whenUnsatisfiable: DoNotSchedule
+        labelSelector:
+          matchLabels:
+            app: nginx
+`}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("spread across zones"))
+	})
+
+	It("accepts pod anti-affinity", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{podSpec: `      affinity:
+        podAntiAffinity:
+          preferredDuringSchedulingIgnoredDuringExecution:
+          - weight: 100
+            podAffinityTerm:
+              topologyKey: failure-domain.beta.kubernetes.io/zone
+              labelSelector:
+                matchLabels:
+                  app: nginx
+`}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("spread across zones"))
+	})
+
+	It("returns message when anti-affinity is only per node", func() {
+		output, err := analyzer.AnalyzeWithConfig(nginxManifest{podSpec: `      affinity:
+        podAntiAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+          - topologyKey: kubernetes.io/hostname
+            labelSelector:
+              matchLabels:
+                app: nginx
+`}.yaml(), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("not spread across zones"))
+	})
+})
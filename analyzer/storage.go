@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const readWriteOnceClaimMessage = "Volume %s uses %s claim %s. Pods on different nodes can not mount it, so replicas do not help when the node fails"
+const hostPathMessage = "Volume %s uses hostPath. Data stays on the node when pod moves to another one"
+const emptyDirMessage = "Volume %s is emptyDir mounted at %s, which looks like persistent data. Data is lost when pod is deleted"
+
+// ReadWriteOncePod is newer than the vendored API types
+const readWriteOncePod = corev1.PersistentVolumeAccessMode("ReadWriteOncePod")
+
+// defaultPersistentPaths are mount paths where applications usually keep data
+var defaultPersistentPaths = []string{"/data", "/var/lib"}
+
+// claimIndex has access modes of PersistentVolumeClaims from the input by namespace and name
+type claimIndex map[string][]corev1.PersistentVolumeAccessMode
+
+func newClaimIndex(objects []*object) claimIndex {
+	claims := claimIndex{}
+	for _, o := range objects {
+		if claim, ok := o.Object.(*corev1.PersistentVolumeClaim); ok {
+			claims[claim.Namespace+"/"+claim.Name] = claim.Spec.AccessModes
+		}
+	}
+	return claims
+}
+
+// singleNode is true when the claim is in the input and can be mounted only on one node
+func (claims claimIndex) singleNode(namespace, name string) (corev1.PersistentVolumeAccessMode, bool) {
+	modes, ok := claims[namespace+"/"+name]
+	if !ok {
+		return "", false
+	}
+	for _, mode := range modes {
+		if mode == corev1.ReadWriteMany || mode == corev1.ReadOnlyMany {
+			return "", false
+		}
+	}
+	for _, mode := range modes {
+		if mode == corev1.ReadWriteOnce || mode == readWriteOncePod {
+			return mode, true
+		}
+	}
+	return "", false
+}
+
+// analyzeStorage checks that volumes do not tie pods to one node or lose data.
+// StatefulSet volumeClaimTemplates create a claim for every replica, so they are not checked.
+func analyzeStorage(w *workload, replicas int32, claims claimIndex, persistentPaths []string) []string {
+	errors := []string{}
+	spec := w.Template.Spec
+	// DaemonSet runs a pod on every node
+	shared := w.Kind == "DaemonSet" || replicas > 1
+	for _, v := range spec.Volumes {
+		switch {
+		case v.PersistentVolumeClaim != nil && shared:
+			if mode, ok := claims.singleNode(w.Namespace, v.PersistentVolumeClaim.ClaimName); ok {
+				errors = append(errors, fmt.Sprintf(readWriteOnceClaimMessage, v.Name, mode, v.PersistentVolumeClaim.ClaimName))
+			}
+		// Node agents run as DaemonSets and use hostPath on purpose
+		case v.HostPath != nil && w.Kind != "DaemonSet":
+			errors = append(errors, fmt.Sprintf(hostPathMessage, v.Name))
+		case v.EmptyDir != nil:
+			for _, mountPath := range mountPaths(spec, v.Name) {
+				if looksPersistent(mountPath, persistentPaths) {
+					errors = append(errors, fmt.Sprintf(emptyDirMessage, v.Name, mountPath))
+				}
+			}
+		}
+	}
+	return errors
+}
+
+func mountPaths(spec corev1.PodSpec, volume string) []string {
+	paths := []string{}
+	for _, c := range spec.Containers {
+		for _, m := range c.VolumeMounts {
+			if m.Name == volume {
+				paths = append(paths, m.MountPath)
+			}
+		}
+	}
+	return paths
+}
+
+// looksPersistent is true when mount path is one of persistent paths or is inside of one
+func looksPersistent(mountPath string, persistentPaths []string) bool {
+	mountPath = path.Clean(mountPath)
+	for _, p := range persistentPaths {
+		p = path.Clean(p)
+		if mountPath == p || strings.HasPrefix(mountPath, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
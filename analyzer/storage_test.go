@@ -0,0 +1,87 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Analyze storage", func() {
+	claim := func(accessModes string) runtime.Object {
+		return decode(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  accessModes: ` + accessModes + `
+  resources:
+    requests:
+      storage: 1Gi
+`)
+	}
+	workload := func(kind, replicas, volume string) runtime.Object {
+		return decode(`apiVersion: apps/v1
+kind: ` + kind + `
+metadata:
+  name: db
+spec:
+  replicas: ` + replicas + `
+  template:
+    spec:
+      containers:
+      - name: db
+        image: db:1.0
+        volumeMounts:
+        - name: data
+          mountPath: /var/lib/db
+      volumes:
+      - name: data
+` + volume)
+	}
+	claimVolume := "        persistentVolumeClaim:\n          claimName: data\n"
+
+	It("returns message when replicas share ReadWriteOnce claim", func() {
+		Expect(workloadErrors(analyzer.Options{}, claim("[ReadWriteOnce]"), workload("Deployment", "3", claimVolume))).To(ContainElement(
+			"Volume data uses ReadWriteOnce claim data. Pods on different nodes can not mount it, so replicas do not help when the node fails"))
+	})
+
+	It("returns message for ReadWriteOncePod claim", func() {
+		Expect(workloadErrors(analyzer.Options{}, claim("[ReadWriteOncePod]"), workload("Deployment", "2", claimVolume))).To(ContainElement(ContainSubstring("ReadWriteOncePod claim data")))
+	})
+
+	It("is successful for ReadWriteMany claim", func() {
+		Expect(workloadErrors(analyzer.Options{}, claim("[ReadWriteOnce, ReadWriteMany]"), workload("Deployment", "3", claimVolume))).NotTo(ContainElement(ContainSubstring("Volume data")))
+	})
+
+	It("does not check claims that are not in the input", func() {
+		Expect(workloadErrors(analyzer.Options{}, workload("Deployment", "3", claimVolume))).NotTo(ContainElement(ContainSubstring("Volume data")))
+	})
+
+	It("returns message for hostPath volume", func() {
+		Expect(workloadErrors(analyzer.Options{}, workload("Deployment", "3", "        hostPath:\n          path: /srv\n"))).To(ContainElement(
+			"Volume data uses hostPath. Data stays on the node when pod moves to another one"))
+	})
+
+	It("allows hostPath for DaemonSet", func() {
+		Expect(workloadErrors(analyzer.Options{}, workload("DaemonSet", "null", "        hostPath:\n          path: /srv\n"))).NotTo(ContainElement(ContainSubstring("hostPath")))
+	})
+
+	It("returns message for emptyDir mounted at persistent path", func() {
+		Expect(workloadErrors(analyzer.Options{}, workload("Deployment", "3", "        emptyDir: {}\n"))).To(ContainElement(
+			"Volume data is emptyDir mounted at /var/lib/db, which looks like persistent data. Data is lost when pod is deleted"))
+	})
+
+	It("uses persistent paths from config", func() {
+		result, err := analyzer.AnalyzeObjects([]runtime.Object{workload("Deployment", "3", "        emptyDir: {}\n")}, analyzer.Options{
+			Config: analyzer.Config{Profile: analyzer.Profile{PersistentPaths: []string{"/srv"}}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Workloads[0].Errors).NotTo(ContainElement(ContainSubstring("emptyDir")))
+	})
+
+	It("fails for relative persistent paths", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{PersistentPaths: []string{"data"}}}
+		Expect(config.Validate()).To(MatchError("persistent path data is not absolute"))
+	})
+})
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const recreateStrategyMessage = "Deployment uses Recreate strategy. All pods are stopped on every rollout"
+const maxUnavailableMessage = "maxUnavailable %s allows all %d replicas to be unavailable during rollout"
+const rolloutStuckMessage = "maxSurge and maxUnavailable are both 0. Rollout will never progress"
+const invalidRollingUpdateMessage = "Rolling update %s %s is invalid: %s"
+
+// Kubernetes uses these values when rollingUpdate parameters are omitted
+var defaultMaxUnavailable = intstr.FromString("25%")
+var defaultMaxSurge = intstr.FromString("25%")
+
+func analyzeStrategy(strategy v1.DeploymentStrategy, replicas int32) []string {
+	if strategy.Type == v1.RecreateDeploymentStrategyType {
+		return []string{recreateStrategyMessage}
+	}
+
+	maxUnavailable := &defaultMaxUnavailable
+	maxSurge := &defaultMaxSurge
+	if strategy.RollingUpdate != nil {
+		if strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = strategy.RollingUpdate.MaxUnavailable
+		}
+		if strategy.RollingUpdate.MaxSurge != nil {
+			maxSurge = strategy.RollingUpdate.MaxSurge
+		}
+	}
+
+	errors := []string{}
+	// Same rounding as the deployment controller: unavailable down, surge up
+	unavailable, err := intstr.GetValueFromIntOrPercent(maxUnavailable, int(replicas), false)
+	if err != nil {
+		return append(errors, fmt.Sprintf(invalidRollingUpdateMessage, "maxUnavailable", maxUnavailable.String(), err))
+	}
+	if _, err := intstr.GetValueFromIntOrPercent(maxSurge, int(replicas), true); err != nil {
+		return append(errors, fmt.Sprintf(invalidRollingUpdateMessage, "maxSurge", maxSurge.String(), err))
+	}
+
+	if unavailable >= int(replicas) {
+		errors = append(errors, fmt.Sprintf(maxUnavailableMessage, maxUnavailable.String(), replicas))
+	}
+	// Deployment controller raises maxUnavailable to 1 when both values resolve to 0,
+	// so rollout is stuck only when both are set to 0 literally
+	if literalZero(maxUnavailable) && literalZero(maxSurge) {
+		errors = append(errors, rolloutStuckMessage)
+	}
+	return errors
+}
+
+// literalZero is true for 0 and 0%
+func literalZero(value *intstr.IntOrString) bool {
+	if value.Type == intstr.Int {
+		return value.IntVal == 0
+	}
+	return value.StrVal == "0%"
+}
+
+// surgePods is how many pods above replicas rollout creates
+func surgePods(strategy v1.DeploymentStrategy, replicas int32) int {
+	if strategy.Type == v1.RecreateDeploymentStrategyType {
+		return 0
+	}
+	maxSurge := &defaultMaxSurge
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxSurge != nil {
+		maxSurge = strategy.RollingUpdate.MaxSurge
+	}
+	surge, err := intstr.GetValueFromIntOrPercent(maxSurge, int(replicas), true)
+	if err != nil {
+		// Invalid maxSurge is reported by strategy rule
+		return 0
+	}
+	return surge
+}
@@ -0,0 +1,83 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze strategy", func() {
+	It("is successful when strategy is not specified", func() {
+		output, err := analyzer.Analyze(nginxManifest{}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("Recreate"))
+		Expect(output).NotTo(HaveMatchingElement("maxUnavailable"))
+		Expect(output).NotTo(HaveMatchingElement("maxSurge"))
+	})
+
+	It("returns message for Recreate strategy", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    type: Recreate`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Recreate"))
+	})
+
+	It("returns message when maxUnavailable covers all replicas", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    type: RollingUpdate
+    rollingUpdate:
+      maxUnavailable: 3`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("maxUnavailable 3"))
+	})
+
+	It("resolves maxUnavailable percentage against replicas", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxUnavailable: 100%`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("maxUnavailable 100%"))
+
+		output, err = analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxUnavailable: 50%`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("maxUnavailable"))
+	})
+
+	It("returns message when both maxSurge and maxUnavailable are 0", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxSurge: 0%
+      maxUnavailable: 0`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("maxSurge and maxUnavailable"))
+	})
+
+	It("does not return message when only maxSurge is 0", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxSurge: 0
+      maxUnavailable: 1`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("maxSurge"))
+		Expect(output).NotTo(HaveMatchingElement("maxUnavailable"))
+	})
+
+	It("does not return message when maxUnavailable percentage rounds down to 0", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxSurge: 0
+      maxUnavailable: 25%`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("Rollout will never progress"))
+	})
+
+	It("returns message for invalid percentage", func() {
+		output, err := analyzer.Analyze(nginxManifest{spec: `  strategy:
+    rollingUpdate:
+      maxUnavailable: lots`}.yaml())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("invalid"))
+	})
+})
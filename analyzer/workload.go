@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workload is a Deployment, StatefulSet, DaemonSet or ReplicaSet converted to apps/v1, or a Job, CronJob or Pod
+type workload struct {
+	metav1.ObjectMeta
+	Kind string
+	// Replicas is nil for DaemonSet that runs a pod on every node, and for Jobs, CronJobs and Pods
+	Replicas *int32
+	Template corev1.PodTemplateSpec
+	// Deployment is set only for deployments
+	Deployment *v1.Deployment
+	// Job is set for Jobs and CronJobs. For CronJob it is the job template
+	Job *batchv1.JobSpec
+	// CronJob is set only for cronjobs
+	CronJob  *batchv1beta1.CronJobSpec
+	Warnings []string
+	// Source is the file or template that produced the workload, if known
+	Source string
+	newer  newerFields
+	// fields is the workload as it was passed, before conversion to apps/v1
+	fields map[string]interface{}
+}
+
+func parseWorkload(yaml []byte) (*workload, error) {
+	o, err := decodeObject(yaml)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkload(o)
+}
+
+func newWorkload(o *object) (*workload, error) {
+	if w, ok := newBatchWorkload(o); ok {
+		return w, nil
+	}
+	if pod, ok := o.Object.(*corev1.Pod); ok {
+		return newPodWorkload(o, pod)
+	}
+	converted, warning, err := convertToAppsV1(o.Object, &o.gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &workload{Source: o.source, newer: o.newer, fields: o.fields}
+	if warning != "" {
+		w.Warnings = []string{warning}
+	}
+	switch t := converted.(type) {
+	case *v1.Deployment:
+		w.Kind = "Deployment"
+		w.ObjectMeta = t.ObjectMeta
+		w.Replicas = t.Spec.Replicas
+		w.Template = t.Spec.Template
+		w.Deployment = t
+	case *v1.StatefulSet:
+		w.Kind = "StatefulSet"
+		w.ObjectMeta = t.ObjectMeta
+		w.Replicas = t.Spec.Replicas
+		w.Template = t.Spec.Template
+	case *v1.DaemonSet:
+		w.Kind = "DaemonSet"
+		w.ObjectMeta = t.ObjectMeta
+		w.Template = t.Spec.Template
+	case *v1.ReplicaSet:
+		if managed(t.ObjectMeta) {
+			return nil, ErrNotAWorkload
+		}
+		w.Kind = "ReplicaSet"
+		w.ObjectMeta = t.ObjectMeta
+		w.Replicas = t.Spec.Replicas
+		w.Template = t.Spec.Template
+	}
+	return w, nil
+}
@@ -0,0 +1,148 @@
+package analyzer_test
+
+import (
+	"github.com/alex-slynko/haornot/analyzer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyze workloads", func() {
+	podTemplate := `  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15
+`
+
+	It("does not warn about apps/v1", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Warnings).To(BeEmpty())
+	})
+
+	It("warns about API version removed from Kubernetes", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("Deployment"))
+		Expect(output.Warnings).To(ConsistOf("extensions/v1beta1 Deployment was removed in Kubernetes 1.16. Use apps/v1 instead"))
+	})
+
+	It("converts rolling update parameters of legacy deployments", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  strategy:
+    rollingUpdate:
+      maxUnavailable: 100%
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("maxUnavailable 100%"))
+		Expect(output.Warnings).To(ConsistOf(ContainSubstring("apps/v1beta1 Deployment")))
+	})
+
+	It("keeps revision history defaults of legacy deployments", func() {
+		config := analyzer.Config{Profile: analyzer.Profile{MinRevisionHistoryLimit: threshold(3)}}
+		output, err := analyzer.AnalyzeWithConfig([]byte(`apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`+podTemplate), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(HaveMatchingElement("revisionHistoryLimit"))
+
+		output, err = analyzer.AnalyzeWithConfig([]byte(`apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+`+podTemplate), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("revisionHistoryLimit is 2, lower than required 3"))
+	})
+
+	It("uses legacy Recreate strategy", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+  strategy:
+    type: Recreate
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HaveMatchingElement("Recreate"))
+	})
+
+	It("analyzes statefulsets", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: apps/v1beta2
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 1
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("StatefulSet"))
+		Expect(output.Name).To(Equal("db"))
+		Expect(output).To(HaveMatchingElement("At least 2 replicas required for statefulset"))
+		Expect(output.Warnings).To(ConsistOf("apps/v1beta2 StatefulSet was removed in Kubernetes 1.16. Use apps/v1 instead"))
+	})
+
+	It("analyzes daemonsets without replicas", func() {
+		output, err := analyzer.Analyze([]byte(`apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Kind).To(Equal("DaemonSet"))
+		Expect(output).NotTo(HaveMatchingElement("replicas"))
+		Expect(output).To(HaveMatchingElement("readiness probe"))
+		Expect(output.Warnings).To(ConsistOf(ContainSubstring("extensions/v1beta1 DaemonSet")))
+	})
+
+	It("keeps template that produced the workload", func() {
+		output, err := analyzer.Analyze([]byte(`# Source: nginx/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3
+` + podTemplate))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Source).To(Equal("nginx/templates/deployment.yaml"))
+	})
+
+	It("returns ErrNotAWorkload for other kinds", func() {
+		_, err := analyzer.Analyze([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`))
+		Expect(err).To(Equal(analyzer.ErrNotAWorkload))
+	})
+})
@@ -0,0 +1,102 @@
+package chart
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	hapichart "k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// Same release name as `helm template` uses by default
+const releaseName = "RELEASE-NAME"
+const namespace = "default"
+
+// Render renders chart templates without access to cluster or chart repositories.
+// Every rendered template is prefixed with "# Source:" comment, same as in `helm template` output.
+func Render(chartPath string, valueFiles []string) ([]byte, error) {
+	c, err := chartutil.Load(chartPath)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := mergeValueFiles(valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := values.YAML()
+	if err != nil {
+		return nil, err
+	}
+	config := &hapichart.Config{Raw: raw, Values: map[string]*hapichart.Value{}}
+
+	if err := chartutil.ProcessRequirementsEnabled(c, config); err != nil {
+		return nil, err
+	}
+	if err := chartutil.ProcessRequirementsImportValues(c); err != nil {
+		return nil, err
+	}
+
+	options := chartutil.ReleaseOptions{Name: releaseName, Time: timeconv.Now(), Namespace: namespace, IsInstall: true}
+	renderValues, err := chartutil.ToRenderValues(c, config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := engine.New().Render(c, renderValues)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	documents := []string{}
+	for _, name := range names {
+		base := path.Base(name)
+		if base == "NOTES.txt" || strings.HasPrefix(base, "_") {
+			continue
+		}
+		// Every document of multi-document template gets its own source comment
+		for _, document := range strings.Split(rendered[name], "\n---") {
+			document = strings.TrimLeft(strings.TrimPrefix(strings.TrimLeft(document, "\n"), "---"), "\n")
+			if strings.TrimSpace(document) == "" {
+				continue
+			}
+			documents = append(documents, fmt.Sprintf("# Source: %s\n%s", name, document))
+		}
+	}
+	return []byte(strings.Join(documents, "\n---\n")), nil
+}
+
+func mergeValueFiles(valueFiles []string) (chartutil.Values, error) {
+	values := chartutil.Values{}
+	for _, file := range valueFiles {
+		current, err := chartutil.ReadValuesFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", file, err)
+		}
+		mergeValues(values, current)
+	}
+	return values, nil
+}
+
+// mergeValues merges src into dest. Later values files override earlier ones, same as in helm.
+func mergeValues(dest, src map[string]interface{}) {
+	for k, v := range src {
+		nextMap, ok := v.(map[string]interface{})
+		destMap, isMap := dest[k].(map[string]interface{})
+		if !ok || !isMap {
+			dest[k] = v
+			continue
+		}
+		mergeValues(destMap, nextMap)
+	}
+}
@@ -0,0 +1,54 @@
+package chart_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-slynko/haornot/chart"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	chartPath := filepath.Join("..", "fixtures", "chart")
+
+	It("renders templates with default values", func() {
+		output, err := chart.Render(chartPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring("replicas: 1"))
+		Expect(string(output)).To(ContainSubstring("name: RELEASE-NAME-nginx"))
+	})
+
+	It("marks every document with its template", func() {
+		output, err := chart.Render(chartPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+		documents := strings.Split(string(output), "\n---\n")
+		Expect(documents).To(HaveLen(2))
+		Expect(documents[0]).To(HavePrefix("# Source: nginx/templates/deployment.yaml\n"))
+		Expect(documents[1]).To(HavePrefix("# Source: nginx/templates/service.yaml\n"))
+	})
+
+	It("skips notes and helpers", func() {
+		output, err := chart.Render(chartPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(output)).NotTo(ContainSubstring("NOTES.txt"))
+		Expect(string(output)).NotTo(ContainSubstring("_helpers.tpl"))
+	})
+
+	It("overrides values from values files", func() {
+		output, err := chart.Render(chartPath, []string{filepath.Join(chartPath, "values-prod.yaml")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring("replicas: 3"))
+		Expect(string(output)).To(ContainSubstring("image: nginx@sha256"))
+	})
+
+	It("returns error when chart is missing", func() {
+		_, err := chart.Render(filepath.Join("..", "fixtures", "chart_that_should_not_exist"), nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns error when values file is missing", func() {
+		_, err := chart.Render(chartPath, []string{"values_that_should_not_exist.yaml"})
+		Expect(err).To(HaveOccurred())
+	})
+})
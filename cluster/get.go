@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Options select cluster and objects, same as kubectl flags
+type Options struct {
+	// Kubeconfig is the path to kubeconfig file. Default is KUBECONFIG or ~/.kube/config
+	Kubeconfig string
+	Context    string
+	// Namespace is the namespace of the current context when empty
+	Namespace string
+	// Selector is a label selector, e.g. app=api
+	Selector string
+}
+
+type resource struct {
+	// path is the API group path of the resource
+	path   string
+	plural string
+}
+
+// Only resources that can be analyzed are fetched
+var resources = map[string]resource{
+	"deployment":  {path: "/apis/apps/v1", plural: "deployments"},
+	"statefulset": {path: "/apis/apps/v1", plural: "statefulsets"},
+	"daemonset":   {path: "/apis/apps/v1", plural: "daemonsets"},
+	"job":         {path: "/apis/batch/v1", plural: "jobs"},
+	"cronjob":     {path: "/apis/batch/v1", plural: "cronjobs"},
+	"service":     {path: "/api/v1", plural: "services"},
+}
+
+// Short names and plurals, same as in kubectl
+var aliases = map[string]string{
+	"deploy":       "deployment",
+	"deployments":  "deployment",
+	"sts":          "statefulset",
+	"statefulsets": "statefulset",
+	"ds":           "daemonset",
+	"daemonsets":   "daemonset",
+	"jobs":         "job",
+	"cj":           "cronjob",
+	"cronjobs":     "cronjob",
+	"svc":          "service",
+	"services":     "service",
+}
+
+type request struct {
+	resource resource
+	// name is empty when all objects matching the selector are requested
+	name string
+}
+
+// Get fetches objects from cluster. Arguments are the same as in `kubectl get`:
+// TYPE[,TYPE...] [NAME...] or TYPE/NAME...
+func Get(options Options, args []string) ([]runtime.Object, error) {
+	requests, err := parseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range requests {
+		if r.name != "" && options.Selector != "" {
+			return nil, fmt.Errorf("name cannot be provided when a selector is specified")
+		}
+	}
+
+	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+	loader.ExplicitPath = options.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: options.Context, Context: clientcmdapi.Context{Namespace: options.Namespace}}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: scheme.Codecs}
+	client, err := rest.UnversionedRESTClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, r := range requests {
+		path := fmt.Sprintf("%s/namespaces/%s/%s", r.resource.path, namespace, r.resource.plural)
+		if r.name != "" {
+			path += "/" + r.name
+		}
+		get := client.Get().AbsPath(path)
+		if options.Selector != "" {
+			get = get.Param("labelSelector", options.Selector)
+		}
+		raw, err := get.Do().Raw()
+		if err != nil {
+			return nil, fmt.Errorf("can not get %s %s: %s", r.resource.plural, r.name, err)
+		}
+		decoded, err := runtime.Decode(unstructured.UnstructuredJSONScheme, raw)
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := decoded.(*unstructured.UnstructuredList); ok {
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		objects = append(objects, decoded)
+	}
+	return objects, nil
+}
+
+func parseArgs(args []string) ([]request, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("resource type is required, e.g. deployment/api")
+	}
+	requests := []request{}
+	if !strings.Contains(args[0], "/") {
+		for _, kind := range strings.Split(args[0], ",") {
+			r, err := lookup(kind)
+			if err != nil {
+				return nil, err
+			}
+			if len(args) == 1 {
+				requests = append(requests, request{resource: r})
+			}
+			for _, name := range args[1:] {
+				requests = append(requests, request{resource: r, name: name})
+			}
+		}
+		return requests, nil
+	}
+
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "/", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("%s must be in TYPE/NAME format", arg)
+		}
+		r, err := lookup(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request{resource: r, name: parts[1]})
+	}
+	return requests, nil
+}
+
+func lookup(name string) (resource, error) {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	r, ok := resources[name]
+	if !ok {
+		return resource{}, fmt.Errorf("resource %s can not be analyzed", name)
+	}
+	return r, nil
+}
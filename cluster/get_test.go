@@ -0,0 +1,109 @@
+package cluster_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/cluster"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: payments
+current-context: test
+`
+
+const deploymentList = `{"kind": "DeploymentList", "apiVersion": "apps/v1", "metadata": {},
+"items": [{"metadata": {"name": "api", "namespace": "payments"}}, {"metadata": {"name": "web", "namespace": "payments"}}]}`
+
+var _ = Describe("Get", func() {
+	var (
+		server   *httptest.Server
+		requests []string
+		options  cluster.Options
+		dir      string
+	)
+
+	BeforeEach(func() {
+		requests = []string{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.String())
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/apis/apps/v1/namespaces/payments/deployments/api":
+				fmt.Fprint(w, `{"kind": "Deployment", "apiVersion": "apps/v1", "metadata": {"name": "api", "namespace": "payments"}}`)
+			case "/apis/apps/v1/namespaces/payments/deployments", "/apis/apps/v1/namespaces/default/deployments":
+				fmt.Fprint(w, deploymentList)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`)
+			}
+		}))
+
+		var err error
+		dir, err = ioutil.TempDir("", "kubeconfig")
+		Expect(err).NotTo(HaveOccurred())
+		path := filepath.Join(dir, "config")
+		Expect(ioutil.WriteFile(path, []byte(fmt.Sprintf(kubeconfig, server.URL)), 0644)).To(Succeed())
+		options = cluster.Options{Kubeconfig: path}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("gets object by type and name in the namespace of the context", func() {
+		objects, err := cluster.Get(options, []string{"deployment/api"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(HaveLen(1))
+		Expect(objects[0].(*unstructured.Unstructured).GetName()).To(Equal("api"))
+	})
+
+	It("lists objects by label selector and sets their kind", func() {
+		options.Namespace = "default"
+		options.Selector = "app=api"
+		objects, err := cluster.Get(options, []string{"deploy"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal([]string{"/apis/apps/v1/namespaces/default/deployments?labelSelector=app%3Dapi"}))
+		Expect(objects).To(HaveLen(2))
+		Expect(objects[1].GetObjectKind().GroupVersionKind().Kind).To(Equal("Deployment"))
+	})
+
+	It("lists several types", func() {
+		_, err := cluster.Get(options, []string{"deployments,svc"})
+		Expect(err).To(MatchError(ContainSubstring("can not get services")))
+		Expect(requests).To(Equal([]string{"/apis/apps/v1/namespaces/payments/deployments", "/api/v1/namespaces/payments/services"}))
+	})
+
+	It("fails when object does not exist", func() {
+		_, err := cluster.Get(options, []string{"deployments", "api", "missing"})
+		Expect(err).To(MatchError(ContainSubstring("can not get deployments missing")))
+	})
+
+	It("fails when name and selector are both set", func() {
+		options.Selector = "app=api"
+		_, err := cluster.Get(options, []string{"deployment/api"})
+		Expect(err).To(MatchError("name cannot be provided when a selector is specified"))
+	})
+
+	It("fails for resources that can not be analyzed", func() {
+		_, err := cluster.Get(options, []string{"pods"})
+		Expect(err).To(MatchError("resource pods can not be analyzed"))
+	})
+})
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/formatter"
+	"github.com/alex-slynko/haornot/gitdiff"
+	"github.com/alex-slynko/haornot/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// runDiff analyzes manifests changed since the base revision and reports only what the change made worse
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	base := flags.String("base", "HEAD", "Git revision the change is compared with, e.g. origin/main")
+	optionFlags := addOptionFlags(flags)
+	minScore := addMinScoreFlag(flags)
+	flags.Parse(args)
+	options := optionFlags.load()
+
+	files, err := gitdiff.Files(*base, flags.Args(), func(path string) bool { return manifestExtensions[filepath.Ext(path)] })
+	if err != nil {
+		failWith(err.Error())
+	}
+	// All manifests are analyzed, so services in changed files are matched with workloads from other files
+	before := []runtime.Object{}
+	after := []runtime.Object{}
+	invalid := []error{}
+	changed := map[string]bool{}
+	for _, file := range files {
+		// Base is nil for added files and Current is nil for deleted files. Empty manifest would be decoded as a Deployment
+		if file.Base != nil {
+			// Base version was checked before, so it is not reported
+			objects, _ := decodeManifests(file.Base, options.Workers)
+			before = append(before, withSource(objects, file.Path)...)
+		}
+		if file.Current != nil {
+			objects, errors := decodeManifests(file.Current, options.Workers)
+			after = append(after, withSource(objects, file.Path)...)
+			if !file.Changed {
+				continue
+			}
+			for _, obj := range objects {
+				changed[objectKey(obj)] = true
+			}
+			for _, err := range errors {
+				invalid = append(invalid, fmt.Errorf("%s: %s", file.Path, err))
+			}
+		}
+	}
+
+	result, err := analyzer.Diff(before, after, options)
+	if err != nil {
+		failWith(err.Error())
+	}
+	result = onlyChanged(result, changed)
+	if len(result.Workloads)+len(result.Services)+len(result.Others)+len(result.Failures)+len(invalid) == 0 {
+		success(formatter.ImageFormatter{}, fmt.Sprintf("No workloads changed since %s", *base))
+		return
+	}
+	// Changed services are matched with workloads from unchanged files, so they can be reported alone
+	report(result, invalid, *minScore, false)
+}
+
+// withSource sets file as source of objects that do not have one, so resources with the same name in different files are compared separately
+func withSource(objects []runtime.Object, file string) []runtime.Object {
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		annotations := accessor.GetAnnotations()
+		if annotations[analyzer.SourceAnnotation] != "" {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[analyzer.SourceAnnotation] = file
+		accessor.SetAnnotations(annotations)
+	}
+	return objects
+}
+
+// onlyChanged keeps messages and failures of resources from changed files
+func onlyChanged(result *analyzer.Result, changed map[string]bool) *analyzer.Result {
+	filtered := &analyzer.Result{}
+	for _, err := range result.Failures {
+		if objectErr, ok := err.(*analyzer.ObjectError); !ok || changed[resourceKey(objectErr.Source, objectErr.Kind, objectErr.Namespace, objectErr.Name)] {
+			filtered.Failures = append(filtered.Failures, err)
+		}
+	}
+	keep := func(messages []*types.Message) []*types.Message {
+		kept := []*types.Message{}
+		for _, msg := range messages {
+			if changed[resourceKey(msg.Source, msg.Kind, msg.Namespace, msg.Name)] {
+				kept = append(kept, msg)
+			}
+		}
+		return kept
+	}
+	filtered.Workloads = keep(result.Workloads)
+	filtered.Services = keep(result.Services)
+	filtered.Others = keep(result.Others)
+	return filtered
+}
+
+func objectKey(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return resourceKey(accessor.GetAnnotations()[analyzer.SourceAnnotation], obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName())
+}
+
+func resourceKey(source, kind, namespace, name string) string {
+	return source + ":" + kind + "/" + namespace + "/" + name
+}
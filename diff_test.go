@@ -0,0 +1,144 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("diff", func() {
+	var (
+		dir     string
+		session *gexec.Session
+	)
+
+	git := func(args ...string) {
+		command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		command.Dir = dir
+		out, err := command.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "diff")
+		Expect(err).NotTo(HaveOccurred())
+		git("init", "-q")
+		copyFixture("nginx.yml", path.Join(dir, "nginx.yml"))
+		copyFixture("bad_nginx.yml", path.Join(dir, "legacy.yml"))
+		git("add", "-A")
+		git("commit", "-q", "-m", "base")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		command := exec.Command(pathToCLI, "diff", "--base", "HEAD")
+		command.Dir = dir
+		session, err = gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when nothing changed", func() {
+		It("exits with 0 status code", func() {
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out).To(gbytes.Say("No workloads changed since HEAD"))
+		})
+	})
+
+	Context("when only unrelated manifests changed", func() {
+		BeforeEach(func() {
+			contents, err := ioutil.ReadFile(path.Join(dir, "legacy.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(path.Join(dir, "legacy.yml"), append(contents, []byte("# comment\n")...), 0644)).To(Succeed())
+		})
+
+		It("does not report existing findings", func() {
+			Eventually(session).Should(gexec.Exit(0))
+		})
+	})
+
+	Context("when only a service is added for workloads in unchanged files", func() {
+		BeforeEach(func() {
+			service := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+spec:
+  ports:
+    - port: 80
+  selector:
+    app: nginx
+`)
+			Expect(ioutil.WriteFile(path.Join(dir, "service.yml"), service, 0644)).To(Succeed())
+		})
+
+		It("matches the service with workloads from other files and reports only the service", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Service nginx"))
+			Expect(session.Out).To(gbytes.Say("Service sends traffic to pod nginx of deployment nginx that does not have readiness probe"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("does not match"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("only deployments"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("Deployment nginx"))
+		})
+	})
+
+	Context("when replicas decreased", func() {
+		BeforeEach(func() {
+			copyFixture("bad_nginx.yml", path.Join(dir, "nginx.yml"))
+		})
+
+		It("reports the regression", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Replicas decreased from 3 to 1"))
+		})
+	})
+
+	Context("when a manifest is deleted", func() {
+		BeforeEach(func() {
+			git("rm", "-q", "legacy.yml")
+		})
+
+		It("does not report the deleted workload", func() {
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Out).To(gbytes.Say("No workloads changed since HEAD"))
+		})
+	})
+
+	Context("when a manifest is added", func() {
+		BeforeEach(func() {
+			manifest := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+`)
+			Expect(ioutil.WriteFile(path.Join(dir, "api.yml"), manifest, 0644)).To(Succeed())
+		})
+
+		It("reports findings of the new workload only", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("api"))
+			Expect(session.Out.Contents()).To(ContainSubstring("At least 2 replicas required for deployment"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("Deployment  "))
+		})
+	})
+})
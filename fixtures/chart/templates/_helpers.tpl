@@ -0,0 +1,3 @@
+{{- define "nginx.labels" -}}
+app: {{ .Chart.Name }}
+{{- end -}}
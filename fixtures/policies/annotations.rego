@@ -0,0 +1,10 @@
+package k8srequiredannotations
+
+# Gatekeeper policy, same as in ConstraintTemplate
+violation[{"msg": msg, "details": {"missing": missing}}] {
+	input.review.kind.kind == "Deployment"
+	provided := {annotation | input.review.object.metadata.annotations[annotation]}
+	missing := {"owner"} - provided
+	count(missing) > 0
+	msg := sprintf("%s %s must have owner annotation", [input.review.kind.kind, input.review.name])
+}
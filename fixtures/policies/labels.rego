@@ -0,0 +1,12 @@
+package kubernetes.labels
+
+deny[msg] {
+	not input.metadata.labels.team
+	msg := sprintf("%s %s does not have team label", [input.kind, input.metadata.name])
+}
+
+warn[{"msg": msg}] {
+	input.kind == "ConfigMap"
+	not input.metadata.labels.app
+	msg := sprintf("ConfigMap %s does not have app label", [input.metadata.name])
+}
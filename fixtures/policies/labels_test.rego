@@ -0,0 +1,5 @@
+package kubernetes.labels
+
+test_deny_without_team {
+	deny[_] with input as {"kind": "Deployment", "metadata": {"name": "nginx"}}
+}
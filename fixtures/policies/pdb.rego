@@ -0,0 +1,14 @@
+package kubernetes.pdb
+
+# Every deployment must be covered by PodDisruptionBudget from the same input
+deny[msg] {
+	input.kind == "Deployment"
+	not has_budget
+	msg := sprintf("Deployment %s is not covered by PodDisruptionBudget", [input.metadata.name])
+}
+
+has_budget {
+	budget := data.haornot.objects[_]
+	budget.kind == "PodDisruptionBudget"
+	budget.spec.selector.matchLabels == input.spec.selector.matchLabels
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/alex-slynko/haornot/types"
 )
 
-type ImageFormatter struct{}
+type ImageFormatter struct {
+	// HideImages prints only text
+	HideImages bool
+}
 
 const success = "R0lGODlhXQBdAPEAAJSUlCcnJ////////yH5BAEEAAMAIf4mRWRpdGVkIHdpdGggZXpnaWYuY29tIG9ubGluZSBHSUYgbWFrZXIAIf8LTkVUU0NBUEUyLjADAQAAACH/C3htcCBkYXRheG1w/z94cGFja2V0IGJlZ2luPSLvu78iIGlkPSJXNU0wTXBDZWhpSHpyZVN6TlRjemtjOWQiPz4gPHg6eG1wbXRhIHhtbG5zOng9ImFkb2JlOm5zOm1ldGEvIiB4OnhtcHRrPSJBZG9iZSBYTVAgQ29yZSA1LjAtYzA2MCA2MS4xMzQ3NzcsIDIwMTAvMDIvMTItMTc6MzI6MDAgICAgICAgICI+PHJkZjpSREYgeG1sbnM6cmRmPSJodHRwOi8vd3d3Lncub3JnLzE5OTkvMDIvMjItcmRmLXN5bnRheC1ucyMiPiA8cmRmOkRlc2NyaXB0aW9uIHJmOmFib3V0PSIiIP94bWxuczp4bXBNTT0iaHR0cDovL25zLmFkb2JlLmNvbS94YXAvMS4wL21tLyIgeG1sbnM6c3RSZWY9Imh0dHA6Ly9ucy5hZG9iZS5jb20veGFwLzEuMC9zVHBlL1Jlc291cmNlUmVmIyIgeG1sbnM6eG1wPSJodHRwOi8vbnMuYWRvYmUuY29tL3hhcC8xLjAvIiB4bXBNTTpPcmlnaW5hbERvY3VtZW50SUQ9InhtcC5kaWQ6OUM5QjY0NEVBNUJDRTcxMTk2NTQ5OTgzOUEzQUY5OSIgeG1wTU06RG9jdW1lbnRJRD0ieG1wLmRpZDoyRUFFMEM0RUJDQjExMUX/N0E4MzY3MkM3RThFODk4QiIgeG1wTU06SW5zdGFuY2VJRD0ieG1wLmlpZDoyRUFFMEM0REJDQjExMUU3QTgzNkE3MkM3RUU4OThCIiB4bXA6Q3JlYXRvclRvb2w9IkFkb2JlIFBob3Rvc2hvcCBDUzUgV2luZG93cyI+IDx4cE1NOkRlcml2ZWRGcm9tIHN0UmVmOmluc3RhbmNlSUQ9InhtcC5paWQ6QTA5QjY0NEVBNUJDRTcxMTk2NTQ5OTgzOUEzQUYyOTkiIHN0UmVmOmRvY3VtZW50SUQ9InhtcC5kaWQ6OUM5QjY0NEE1QkNFNzExOTY1NDk5ODM5QTNB/0YyOTkiLz4gPC9yZGY6RGVzY3JpcHRpb24+IDwvcmRmOlJERj4gPC94OnhtcG1ldGEgPD94cGFja2V0IGVuZD0iciI/PgH//v38+/r5+Pf29fTz8vHw7+7t7Ovq6ejn5uXk4+Lh4N/e3dzb2tnY19XU09LR0M/OzczLysnIx8bFxMPCwcC/vr28u7q5uLe2tbSzsrGwr66trKuqqainpqWko6KhoJ+enZybmpmYl5aVlJOSkZCPjo2Mi4qJiIeGhYSDgoGAf359fHt6eXh3dnV0c3JxcG9ubWxramloZ2ZlZGNiYWBfXl1cW1pZWFdWVVRTUlFQT05NTEtKSUhHRkZFRENCQUA/Pj08Ozo5ODc2NTQzMjEwLy4tLCsqKSgnJiUkIyIhIB8eHRwbGhkYFxYVFBMSERAPDg0MCwoJCAcGBQQDAgEAACwAAAAAXQBdAAAC/pyPqcvtD6OctNqLs968+w+G4kiW5omm6sq27gvH8qwEAWDbtAoIvt8DCHOBHSjwSyqTwmHOaEH+cLbmchl0PqELpBBYIzZ7VyaOiwgOyIAI8WbFog3qNVAXzX0FRXTdDjRGVEHWR6PjYwhYJiC41VV42CNlSAm3xzhGhQeo+EL2U5kYVhWXaerJIjUlyuemZ8qYqiL11XbwhyEWC0OZm+tR1Qij9js8AryiNorriux8MztiyTxAOT26Sjt5bdBtLR2VeAOWQn3rXZ19pju8in5CTg7vZchWzvCYoEZ+jOLrzw49JeGsHRvS7FYkHo2qCUzw5kGthd2SmVjVKNrD/gygMqZzVasFRjCTNFxiZ/DMQlUEv4Hgh4/lJoPOQgSpVbCEFwQugwUJRSzgTgecrKHMxySnCWPwagTak/OdUp0lExLteOfBzRkuLUJ0BMEry5oDHH4Qu2Lox6kVer745pZDXJEUPYZQyzUSWo4B82bciwHwWFsi5sbAyJaC4cNmNiVGSnZHP0aNtCQWrAoULKxKsgxagHlbVoibOXfWslJSTAi7TGN5THX1hdamj74FSgJOUhmpL4KyTUt2vN+wOWx1QTytcNF2/5lFjjvecxfvnAO/Pf1IdurRsfWVsar4hJBQNOfuzXV04e400J9Vf2i7XPYzQs+Gn76pCDZQTqSg4L+DfRmQV993uS2nnIEHNkeXfOsFIp4GAGY2hX7nIRgPRtcdYd5h4fk2RXz4aRdieiOSGFkv+kyz4hwuvghjjDLOSGONNt6I4w4FAAAh+QQJBAADACwAAAAAXQBdAAAC/pyPqcvtD6OctNqLs968+w+G4kiW5omm6sq27gvH8qwEAWDbtAoIvt8DCHOBHSjwSyqTwmHOaEH+cLbmchl0PqELpBBYIzZ7VyaOiwgOyIAI8WbFog3qNVAXzX0FRXTdDjRGVEHWR6PjYwhYJiC41VV42CNlSAm3xzhGhQeo+EL2U5kYVhWXaerJIjUlyuemZ8qYqiL11XbwhyEWC0OZm+tR1Qij9js8AryiNorriux8MztiyTxAOT26Sjt5bdBtLR2VeAOWQn3rXZ19pju8in5CTg7vZchWzvCYoEZ+jOLrzw49JeGsHRvS7FYkHo2qCUzw5kGthd2SmVjVKNrD/gygMqZzVasFRjCTNFxiZ/DMQlUEv4Hgh4/lJoPOQgSpVbCEFwQugwUJRSzgTgecrKHMxySnCWPwagTak/OdUp0lExLteOfBzRkuLUJ0BMEry5oDHH4Qu2Lox6kVer745pZDXJEUPYZQyzUSWo4B82bciwHwWFsi5sbAyJaC4cNmNiVGSnZHP0aNtCQWrAoULKxKsgxagHlbVoibOXfWslJSTAi7TGN5THX1hdamj74FSgJOUhmpL4KyTUt2vN+wOWx1QTytcNF2/5lFjjvecxfvnAO/Pf1IdurRsfWVsar4hJBQNOfuzXV04e400J9Vf2i7XPYzQs+Gn76pCDZQTqSg4L+DfRmQV993uS2nnIEHNkeXfOsFIp4GAGY2hX7nIRgPRtcdYd5h4fk2RXz4aRdieiOSGFkv+kyz4hwuvghjjDLOSGONNt6I4w4FAAAh+QQJBAADACwAAAAAXQBdAAAC/pyPqcvtD6OctNqLs968+w+G4kiW5omm6sq27gvH8kzX9o3n+s73/g+sBYKigACABASWS+LFKIhKp8cks+lcQKeAI/WbRF6HQeg2rAx7v2BxzwgYBLrs8HyZRrKPZF03LheltlclNjYnpWSD9WcAdXA1SIjG1QfzN9Q4oNkQmURIpfiylSlAxknhqQd2KbUUdXr04Sn7QhdnZtBlCYK6khtoGszrAdwCDOcIO2LMAqzZPFs7J4rCCV3r+NGIKJySrFvLCY6Fwe2qwrm8mQ21SiyBWLpuPb0+TicI7+i22Zfrbl+RKMq80ZNjx0FAOpCWufulj9zBClsEHcDmLZ2g/luvMl64BSgcIEzO8rmCIzBeuXBDHrroxufRQFPdUp7YJZIZLDo2TTzLxoDXyqCCLMb4mdIOomoMTIaEAXBiApOJIOTriUKiRwV4vCCBYAZrPUDROpRtRZbPQKYyNPny8DYGTnZPtwGV8bMu3K1H9bETm0qqXFeCN5w9tirUlWJ3RzmlmsgKEwtxWZjEQm3NJENDFVTWCAveoU+glJYrnCKf3ghMJO0hDRhENyWxFbZOHAoquhNMFL9EPQK3WhVLR8Eczht41opsZzZHPKWzNL5QVdee8Ll6outgCfqJHuLwDJiTzRrVcZx7U+V4qzJGjt7vXvnfG2s4oz45fA5OSvOXEI9BRTHhwJ4FHUXm33QJdnJcIZy1wBNvDfqW1S2/UAMZdcUct9pyzC3YCYWt0FeEajWQ5NNiNEiXRYsuvghjjDLOSGONHBQAACH5BAkEAAMALAAAAABdAF0AAAL+nI+py+0Po5y02ouz3rz7D4biSJbmiabqyrbuC8fyTNf2jef6zvf+DwwKh8Si8YhMKpfMpvMJjUqnVFjgeq0aroCu4CsAhLvYgDMATqvV4i6gXER/3eGyW7xO3+E8uXnQxmDnlQfW9oY1kxVoIPYnMUhY+Ojix+i3URZA90XJIjcWttU5gvkC6vVnGrLagvdGOoAGQDIbE2jKKKLbsik6+8cLIryCqUvcYfnWS3osoPp84Fkh98ocfRnr5zs9ATp3baY9rtYtTbtFiQdmXrJN/ujr+AC+ehhb3Glqi1E99+gI2Ql5ztBhWLds1DNwlfSQEegtkbRO+K45FCViFkP+Kwi5jVgXTQa8j4YUjRRExsymhA1Q0dhXMQHCNO0UsowBrBFGlCBvCvpio2AEPhAgutppNEPSFc5q9pv38iSIVjilfqBqxaqHpSZWWpMFzKkFrhklXWTjRuLBkMVAjnEbptCctGodYHXXM4usPHfuyH2LaNrdsjQFm13T16/cPb7q6CVsSCwWTnzTvlH81yFRpeseU5t82GHKxqLhvq07tCcrLpQzewZtGhGkyCgiaR66ErHkjZVQRzTrM0G10arE3shdp6XbQ14ecvGMo7HTK5kL9bXBJTnu3G3iducrY/K/sg9LdqUrnvZAPSdMzw1ea0/7uGN8a7mPP7/+/fwF+/t/UgAAIfkECQQAAwAsAAAAAF0AXQAAAv6cj6nL7Q+jnLTai7PevPsPhuJIluaJpurKtu4Lx/JM1/aN5/rO9/4PDAqHxKLxiEwql8ym8wmNSqfUqvWKxQS23C0gAPgGu+Rv+AwQpAXstpsd5t7AZ/X7jn+n6/qAbZ1nhxb3RSZ3AAcI8KdWaKgRwOY3ELlYs0eSNhkpsEWDOaJpwAk3CQMqAkoqKcOZqTaq18qaCksJBzfbGWoL1slp6gLMazkgOcxC5ydaWyxKu8JpZhtSeYCJqkLqW0wZ/HAItsl2DZudQhpGPSCq3FDp1as2ib2bvLa1Dlj63oYfa69eNxVrbglA4Iufg4TUkFnL1QIYPIRdIlQ84BCXvf5786B9AMYHogt8Hj0Au+PpRUJmIHzRafNNmJuBEGIuoIVM17qaOxcwy6mLJsJBIhuoYjnjXIJ9Ch2gKhnD1Ts6cSSgUgoDqIerPbNC5cBVqMqvG8LacCnCmgGsp7puUHtL7Au2Frr8M2jI5ol4cr2VoVqHaSN1eOLovWBX0J43XgYtDgRZcORGh8ExhIy58GDHjgvnJZyosoLL/jij0UNIXOPTkvOIYZDPjQTShkXHTtTa9WZHh2oW5OkvJYWXmgc9+hAG+DzRvvPeU87kNnDOx+dwCUnOcuamyZRhn/lr9nc8zDPwFRSIUCFIhmaWx5B78EUTgN5rcVO9u/AS8w2z+P8PYIACDkhgDwUAACH5BAkEAAMALAAAAABdAF0AAAL+nI+py+0Po5y02ouz3rz7D4biSJbmiabqyrbuC8fyTNf2jef6zvf+DwwKh8Si8YjcBZYAQCD5WAaaTYHVCoAipNSr95s9crtfLFkAsD5/0mk6XUZTz+qAuueGx+dudFzwZAfIM1XWxNW35yS49qZ0tTj2JyeF4DhweVNopmcY6XDJaLM5Sbk0EXpHI+jZhiEK6xQDR1XZIYqJxrrmghtS2KgLCeMbcumox9uSaYxmwLipzMIMIvh8V6wipfqrejzIUggn/Xp4Df4dtsKKlTEVyJ1uQI3C7oxB236QOkiP0gnOAqmAucKI8ndiHLdTA9xAeCcN1h2EJhipAkbLnTf+XRRLCKpiECC5CcCYYOEWTs1FWiMpvDk57MVHlAw3vBEpo1PLDYyqEIShR50Ecw5wddSWTEK0KGmCCY2xtEGeKzst3TvKAmUCe/cg8Ks6rauCPItIWjkHdgXWC0Z/QtV6a6K+VXA7vNl1Y22FgbLyin3FxKeXvqPqRgn8pxNhum7HSlIUyWTjWX+fBQYI6dMCvDZCIU6s+aFhmWZAh64wrrMiWxw+piVxOTPrZpNhJ5KdIptHzIBefwB2QjKkmilr31K8uNdoDop9B1/uLqjz4Kl/BdVUnXZyGZueemA5irOI7Nx1lphLTLAuE03DIUY+XaDuarFXp5AO2/TpioMc45s1RVwLS+jiHwUBrqJFggouyGCDDj4IoQsFAAAh+QQJBAADACwAAAAAXQBdAAAC/pyPqcvtD6OctNqLs968+w+G4kiW5omm6sq27gvH8kzX9o3n+s73/g8MCofEWuBYpByPgGZTAAUkEUumEwDNarOAALB6xW7HWOfYyws8x+Sw2izuQtG6QLupBl/ZUQOWfqOWVZVnxseFN+cnIBUoFlV2yOhUlaA4UGYjKAnZtRSRiclo89jpiYQRaicwkCdT9gmiOjcYY9cocmuwujoKwzsCzBtnexkCLMoE9SoA+IGcufqCFCrytygl3SIoiKvhehAqzrrNZVyBiizaCIzl0vvkPFH21BXu207OAn9OUdpMZVkrRe5ciOm2IFaDbs6EXStYrpkqPAghyBsIMJm2/ohH8EWphYGbsjUwYCnqyGUDPUZaLqZYdfBAHpf+WG6hmaIUzgyw1uxEscnXBFQOdGH8CTTl0H4KhDGdFnPhkjg7oWWkoa4pmZ9Wvc14ukuLJwnX1t2ollAhWV9oZ7T1MEsfVrAa4iJ9STeDrk13gZYFAXNQXxRvLegpZQ9H4aJT1/AZvMJoWiuO+ehMM+fwvzunvPSCHBkSJ0pqtQrNERRR5wtZjSAuXZcRaBIoo8yGEFVG7bG0+9h6lNgEzF/Ab19YfAKeVxR5gz0iugL5iOLTmj+jSvy0cEjMrm6Xrdv3CezFtDsXD3VSTuu0gxqvaf6lzvdLwUd2jEfF55dvK8QGT86XcP1twRteLZmwWYH3KVUCTP/9Alsu9E1BYYUWXohhhhpuyCEJBQAAIfkEAQQAAwAsAAAAAF0AXQAAAv6cj6nL7Q+jnLTai7PevPsPhuJIluaJpurKtm4TxPJ7yjGAA8LOB3Rny+l4xOIO8JvYAkKjUSgbCpBJWM5ZhNoSzF3AW110n7ktZBwDhxFjgNlyA38FvvVhWFdGr7073W6A5xCEg0VEFTgFKGhAWGg4VdaFOKBDmTTUBBmJ8+ZHaRk2BikZIzHHtiN6WJp3MZcH6/YD6+oBezB3ZLuCK9LlKqVIEzqilshzyVIswlwZqUrzpezhbOnbkvbH0Zk73Oh1rAIsTjGDje6lw1tTxB5hWTgLvj0g+z2ehSFcby++vi6bPgUzBl1TEPCTMxWZ5HSaFOGdNWjvTMCSY6QiPP580xammFOoThtqFEKZ0tVvRbxvBTdMmySFJAqUMjVcHEgsWQUmGv3RYfKoZ7s+p8oR/IfvBZpBNzIJTZioZr6UqbIIfUZp2hqPuTK5uYq1KlgTXNmM/YTgYBVsH3wxWmuUw8tnSWlBbesFJKCwIUAe2cs3REzA/uZhcBTz7NSxjjYpXixmzyNDnaQ8ZjhFMj8yX02JJTxqU6uNUl2EPjL6cNycyTqD0Ls12eVTapPomm1utcpdmEtb/Lub6rjaH+8u002CbS/kyY1/BC5Q+FDcNp0P9T1COQriLIC1KPtcenLmv8WPAJ/Cegnty83/Ir++7gn007vDz64+Pvbz90w/0C/x1nDybdcfXoYFR10G//UVYHsJmmOZNAVqkNgPC8rlFC2wJTfKftkR8aADwoRoE4jlDfhdN/MdSFiLLr4IY4wyzkhjjTbeCEgBACH5BAkEAAMALAAAAABdAF0AAAL+nI+py+0Po5y02ouz3rz7D4biSJbmiabqyrZuE8Tye8oxgAPCzgd0Z8vpeMTiDvCb2AJCo1EoGwqQSVjOWYTaEsxdwFtddJ+5LWQcA4cRY4DZcgN/Bb71YVhXRq+9O91ugOcQhINFRBU4BShoQFhoOFXWhTigQ5k01AQZifPmR2kZNgYpGSMxx7YjeliadzGXB+v2A+vqAXswd2S7givS5SqlSBM6opbIc8lSLMJcGalK86Xs4Wzp25L2x9GZO9zodawCLE4xg43upcNbU8QeYVk4C749IPs9noUhXG8vvr4umz4FMwZdUxDwkzMVmeR0mhThnTVo70zAkmOkIjz+fNMWpphTqE4bahRCmdLVb0W8bwU3TJskhSQKlDI1XBxILFkFJhr90WHyqGe7PqfKEfyH7wWaQTcyCU2YqGa+lKmyCH1Gadoaj7kyubmKtSpYE1zZjP2E4GAVbB98MVprlMPLZ0lpQW3rBSSgsCFAHtnLN0RMwP7mYXAU8+zUsY42KV4sZs8jQ52kPGY4RTI/Ml9NiSU8alOrjVJdhD4y+nDcnMk6g9C7NdnlU2qT6JptbrXKXZhLW/y7m+q42h/vLtNNgm0v5MmNfwQuUPhQ3DadD/U9QjkK4iyAtSj7XHpy5r/FjwCfwnoJ7cvN/yK/vu4J9NO7w8+uPj728/dMP9Av8dZw8m3XH16GBUddBv/1FWB7CZpjmTQFapDYDwvK5RQtsCU3yn7ZEfGgA8KEaBOI5Q34XTfzHUhYiy6+CGOMMs5IY4023ghIAQAh+QQBBAADACwAAAAAXQBdAAAC/pyPqcvtD6OctNqLs968+w+G4kiW5omm6sq27gvH8kzXVwDkOc4H/g8E2ioBgfGITCoFAKZTN2wUmbrqc4k9BqIKnDEoPIDHvqZgyz2Yl802tQrgxeM/IyCttmf3fDt+0EYGNIcDp+OUdIfXBgLE9NeEFsK4+ChCmfY1gskVuWnJNSUJwjkk+jk6U3TnleqhWTN1dnp5ZuAjIxsH26gZGLPmA1t46zohKmusckpr5mUrpSwaDGy5ivasJcW0VYj9lVytSDtQyO3gdQiN7Cx+C2ohy3sNCO9i9i6gkT46Ve4XY8qdUh4Y/ZJxxIyiSdwA5kr0Kcc5GvIWLkiFC8Ia+mixFG7b8UwZgjUWbRAUw0ZknhyVSiYwR0WCp5YRMkrg1YnjB3I5XXJo9QenB56mZvbSacrOmA1EbchL9EYHGQZNKSbsk6WNIYdRBAqaY2UjH5UBhUIYA1adTxrtaiG1am+nWVVzC8adcTBiTxNV3ZXo+0LgiZMw6Jkg/ALxh7y5FNt9C4MribZ0IYcAzALz0LotGJPQrMLxZsud74pY1dH0Zc4rQHdwfQI2U9bLaD8mm0K2BsplSZOa+ND3YtWZbf+UXNj4bOKtkZ8Gjld5BtROtZVwhjv2xrWvkXCv7f3vRrbUrieM9SN2mD/s27t/Dz++/Pn069v/UAAAOw=="
 const failure = "iVBORw0KGgoAAAANSUhEUgAAAF8AAABsCAYAAAAMom72AAAVhklEQVR4Xu1dDZhcZXV+z70zszu7IcmyCTGGbaJk82PYxNQkKkiJorQoURBJi9Y2IlKtT6nQFv9LqEFBKm0RpWnTkoq2kohKo2koFFqrNCV/SmtiC/lp82dDEpKs2Zmdv+PzDt/d3r07d+69M3dmd3j2PE+ePM/One/n/b77nXPec843gnEZNQRk1Hoe7xjj4I/iJhgHfxz8UURgFLse3/nj4I8iAqPY9fjOHwd/FBEYxa5bfuerKucwBcAM828agHMBdAIoAsgDyAA4CeAYgCMAjgJ4QURKo4h9a9r5qmoBeCWA9wL4JADbB8RCoVA4k0gkuoCKc30MwBcBPC4iXKCmSkvs/A0bNqSWL1++oLu7+1LLst4DYIkfSqpaKpVKuWw2+8Lg4KDd2dnZlUwmk5bF9aoq3wHwByLyk6AH4/p8zIHPY+TAgQMzu7u7P5BKpW5MJBJTCJxIqKEOFgqF/r1795ZOnDjRtnTpUuKu5s0g+nxD+H+1xg4CWAXgSRHhdxsmoWbUsN5Nw6dOnerq6Oh4eyKR+KiIzI/Y375isfixfD7/9K5du46tX7++sGPHDqxYsUJuu+22FIAJALoB9AJYDuCtAKaav3Mx/DCgjuCx9g+NWoRRA19VzwFwBYA1BpjQmKvqQC6X+1Qmk3mgq6vrVOgvAjAKOg1gAYAbAbzTKGcuVCU82D6PutgXoangm4m/CsCdAK4MARpfe1okBQDPAvhTAI8COBzXblRV7v45PO8B/BqAdp9FOG4+fyKuvpsCvrFO3gTgbwC8vALoBJkAUzimnHl2I4DdAE6IiPN5iDWr7RFV5e6/GMCXzIJU0g80Vd8pIv9eWy///62Gg3/27Nkl7e3tj1qWRdvbK7TDM6p6SkS2mUnvAHA6rt1VC0DmDT0fwKeM8k1WeBv2ALhKRP67lj6cXVbrd6t+T1V/AcB3AfCYGWHnqWoxn89/e2Bg4I4jR478eMGCBdztY05Ulc7a+wHcYY6khGeQPAbfIyInog4+9p1vjphPA1hd6XhR1QOnT5++Zc+ePY9edNFFTXdsogLkPK+q3P3vAPBlABMBtHnauotvSpTjMVbwqbxU9VERucwzMCrNrwKgKfnTWgEYC98zCvpyAH8J4DwAXBRHBgG8TUT+KcxYYwV/YGDg7nQ6/fuujqlIB7LZ7FPpdJoDfsmIWQSaynwTyCdRWTtC2uLtIpKtNuHYwKejNHHixOMi4pzvZe9QX5Siqr4vkUh87SWDvpmIqlIH/CqAewG4OSQSeq8XERoQFSUW8M0uGGYKkmNxLUR5HQCsB/BBERmTyrWejaGqdNz4FvymxzK6UUR4RI2QuMC/HcAfultXVT8+hkqWLv6/jKY5WQ/Q1b6by+VenUwm6QO4j6HrROTr3u/FBf4IAkpVB0WEr6Qf3ftDYyf/T6OAGK12+Rao6jYRIYXhyEQR6XePqW7wVZXu+DCTMZ/PZ48fP75zypQpHclkciYA8jiVSCwu2jfMUUQi6yUj1AXFYvFZ27Y5f+J8sYg8FTf41wNY5zrn9NChQw/09PS8f8OGDXZfX9+be3t7V9u2PRvAZJ9FoCn65wA+5t0drbwaqvpZAL8DgPqgR0QYQRuSOHb+fxlWstyWqvaLSJeIkDooy+rVq61rr7328tmzZ9+eSqUuADCpyiJ8HsBnRGSgFYE3Tibt/1sA/K7xA74H4I1eHVcX+IYDIchOOzxGXiMiu3yAk2eeeebCOXPm3JZKpTgYLkIl8orA00P+YpCtPJoLZOZPvfYyAJca/p8EIvFwdB1t/ZeJyOlYFa6q8jw74Gr0KyJCUytQHnvssUmLFy++evLkyXfZts1gh1sxO1QyF4Gcyr2jEWN1JrF27drkrFmzuubOnXtBV1fX4s7OznmWZfEYYaBmngHfrdMcA+QMuS0ROWLMcXJAX3HarXfn3wqAnAaFSneSiNC5iCTbt29PLlq06HW2bX9DRJiJUD7BXIQc/QKasvc3WifwiFy4cOGUvr6+106fPv3d6XT6GhEhhaDmKOW4iBv/+YUk+cxDAK53No2q8uyn0/XuWMAvlUrPu8BaJCLPREK9wsNHjhzpmDZt2mcsy+J56Z4clTLfhH+mTgDww3qdtTVr1sycOnXq2Xnz5nX19vYu6+7u/nAymSRA9UyDvA4dq31OI8YB49g3isjKusFXVVouL7hGacXpNJnz9DUAvklLwdWPcyTxDdsCgAp6V1TdsG/fvg/39PTcY9t2UupEG8CPAXwOwCYR4VEzTFSVyvcLANaKyAfjAJ/BZef82iAi5DcaIoZTZ37Ox3064IJsN/Hg74lI1bhuoVC43rbtPzOx21q3OXkqRrx+VM0yM9wP2U6+xR8XEYZQy1JrxzQpD6vqy82m4f/DbNhGrIJ5G5gsxR1Pv6GScCFoWVAXMQx5GAAnT+kulUpfsiyLQXNvUMR3yGQGDUeYz2Qy9xw9evT23t5ep82qU1XViwD8wDy0UET+oy7w+/v7z5swYcL/sRHD4cR65IRZOFVlMINnPwPffkI9QWVN0o9Kk/+qZU/9DMAThUJhd7FYPHzmzJljhw8fPrZ3797n9+3bd2Lbtm0nN27cGIkUVNV/BPAWM8A2t56qaecXi8V1lmUxtEbw91qW5bcLw+BY1zPGqVkB4Ns1NsSFue7QoUPf7enpiTWyZgLyzhtC55MRsCGJDL6nQeTz+WWpVIrB71EXVf1FAE9XIfPcYyTo5JNoh0eOv4aZrKrSm3/OPNvp1Q2RwR8YGPhmOp2+2tU5rYWGp3WEmazzjKF1qYArMar0yJn+8REA34rTQvOOUVV/3Rglq0Xkj7yfRwKfCq9YLJZs+8U5qeoey7KYnTDmhFaGqmZFhINl9uyXLcuijjjWSMDdQJBWBrCIKeyVTNBI4B88eDA9Y8aMAccsLhQKlySTye+POeRf3BgMZpylgi0WiysTicTDzRynoV7oaG0XkddW6jsS+JlMZmZ7e7uby0m42ctmTi6oL1UlwcVANo/E9mbtdnMiEFdm2pH3uUxEnqgb/Fwud0MymSzHI0ul0t/Ztj3EUwSB0ezPVZWp3sw62yEiFfP5N23a1DFr1qxz+vr6ymZzNTE+BmO0TJq9QESe93teVZmH+vfGj/LViZF2fqFQeM62bWpwyitFZH/QoEfjc+MDlNM2crncNW1tbaQohsm6devOXbVqFRNu2x5++OHkypUrh+IPlca8efPmqVdccQUXiZjdICJ/VQX8fwXwBkME/rbfc6HBP3bs2ISpU6c6McgRNutogFxl8oyuERxyLvQqR9Re5fP57ycSiYvpJN53333tN910U1WPdffu3dPnz59PK4lyq4jcXaV/LiSdOb4hQwSb9/nQ4A8MDNySTqdJDlFWiQgzjsekqCq9UHqz54qIm/wrj1dVmV9Tjhmr6lnLssjLVxWyrdOnT6cCp6wUEVIXI8T91jGlsBrzGhr8QqGQtW27jTyHiKTGmm3voKCqrDph1eEwBtGNkqr+ElNX+Ld8Pn9zKpVi3n+gqCodpguy2ezMdDr9vz7gs23S3jwdGKnzlVDg79+/f9bMmTP3GxPzayJC52FMiqo+wlQ9E87c6QMQa64eIPa7d++eEDZDWlVnlUql37Ntm4GRSrueZB2tQYL+ahHZWzf4mUzmqfb29teb6BJf5UilOM1aJcPzOIrT95VXVXJBtEZGpHPUM1ZV/QsAHwDwIRFhNkZVCdz5PGVM6h8bKppEqKB2R+VzVf0V1k4xACMi1/gNgvHUgYGBaZ2dnY4CrXu8qsrSImZyULnzWK5qPbHDQPDz+fxliUTicTO6O0SE1RpjUlSVlHBnLpdb2NbWNsSbN3qwxgf4EYA+po6LCAvtAiUQfMNPOE4KUyACHZLAXhvwgEvRkhaeUGtp/9atW/lGfDWTyXxh2bJlDNoEiqouBuDol/lhC6mrgm/Cd9xNjti1TipwBnU+oKo8bnjssIr8j6M2p6rTSqXSzZZlfdT5rqr+JJvNXt7R0UFv2VdUlVQC64dpCQaWujsNBYF/P/MozcNM+fZLeo06V9rXZPuOigjNwrrExEnLKSuZTOb8jo4Ohg5DiSHgqBzf5/MFzWazl6bTaXqtI0RVGUhimSrl0yLCuuJQ4gu+qrJi211px6xjJsXGIqpKJ+ddfqRTlE5UlXwLy44ood9OVZ0OgOkuTq5QxW6NwZGu5DCpKu9seJuxBEnghQ4zVgPfcZGdAf1URDjYWIQTMqbev9XboOPRFovFbYlEYlmY9kzUa6hqpFgs5g4ePLiyUChsmT17NgPlM9LpNBeGKTKUeSJCa2ZI3J4ygMj+TzXwqb0XuvpaLyJ+r2aY+boHTWeExBfJuYqeYtgGVZXMZflM7u/vf8PEiROdTAHfJjwZBSgUCnu2bNmyZMWKFcOSc1V1qQlLsq0+EflPD/g8YpjSQumImtJYDXx+dquqfs4kFf0sl8v91iOPPPJQEAMYBJyqsiCalOw59WYjqyp3L2O3TFaaHMTbqyoTsRhiLAspg507d75qyZIlI9IcjdPGTUKeiGMdMj48HM7dIsLUyUgSaGr29/ev7OzsfMiJXrG4TUTonvM1q+lKFFVlqShTKsh113zbk6mNLZ+xxWLxykQiwaLrajue2cTu/CJSAb3VeCpSCrylxFTIu99eWkVMgKJpy4UJdKq8AwsE31gSt7HA11NnReB5DD0YFUBVpdPGFPG6rCdVpSV2v6rybobzqo3DOEKkjZ26WUa53loLQaiqZEFJsXD8NVMUgeC7Xs/uTCZzbzqdvs6Tj882WHF+Z9iJmAywAyLyikjvqedh0w7/ulxEyiyln2Sz2d62tjby+4w5k48fOnqijMEcRfQpmAhF5vRDUb7vfjY0+K5FIGVLu5gpd0N/NgvipEX7VpW4joq6qApzHDCSxor2GVHfviiAmbeGdzAwiscgDXUMHStmZkc+bpy+I4PvWgTaxqzF4n0EXqFJ9iYWBXg/MM4VKxEvFRGWy9Qk9CpLpdJ8y7ICd30tHZhCPwbAGacmV8OiPsd75ZHD4gi39x+5m5rBdy0C7WB6wrwoyCtUplcB2OzsEFVldvBNAGaJSE1loE4FpFH+pI5r3n2ueRALvtVvZmEegAt9iEd65LT5R0TIoqJfN/iuwfO1ZAk8lbC3XSpn2sjMpedry5waJhLVlKZXLBbX5HK5T6ZSqTtt2/ZLGw/EwpTq8CihHmPCLedQTZgSz8KHUBnKQQOIDXzXIrDsca0pDqvW/7RaeB0qPF7dePLkSZkyZcqSKsV3Ffs29jmdR2YV0GQOEm4cOpxXikhoziioUX4eO/iuRegwmcNOerR3PBVL4oMGTR4nm80+ODg4ODhp0qTzw7w9qsqxMBJH29xvPE7FCzFxM5O0il4Xx9HmnVvDwHctAm1iWgakAbz90UoJHU0yZt6ZYrFIMAdt254jIhXpXlVlOjbLM3kvBPn2SkLAeU8C00BIS/ASO9LSm8xYqbNIgdSkm4I2UsPBdy0Cz1ZaQd6ST5qtHwnDBpraJlackBuikr1ZRHgdr3NlIy0w3oHDGl4/H4KAPwmAWcNPe/kYVWU0itYYdz/7IFPZkCzspoFvALrEpGw4/TpllfyYZicVHzn+ShdpkA/iLqdOkVKpREqh2N/fv2by5MkTLMtiohTzcfyEbxgrHHk/ppN/M+JZ4z8w0YljLJgy0KBNXNPnzQafu4lKixwLuR2anPeYK2DcE+BnJKqYJ8Ndx4DFk6pKC6kMfKFQAK/5Zbp6lWJCx8qiGbwnDBflZkkZGhQREnENkWaDz9eZlgb75Xn9rPEeec3un5hc9qGTyrz2fHaYEnyxPg1+oPNDkm3sh/GHH0Q5NlSVZac849nnAyLCN6oh0mzw/9qYd+x3RJmMKRam607H7AaTfDRsjD6XKJFmIMdCepngM9BPHieymLx6tsd+PyEirK9tiDQbfCpDh/YlnVzVM6UnOzg4OCORSCwVkXeIyNWMuVqW5R43lStvKamZmnYjq6osNXUyzd4rIk54MvYFaDb45Ep4E2v5WvUwZ7AHGI6XR4pTQ0vz8JKo7VRD0VPE9hYRcXKWWh58XoHoBDMig8/ZqyojTg74vNP4W3Gi4kkcYL4lvduGSLN3Pk3BMp8TJb/Fs/vdgf259dxlXAlRVZ0LwPn1iFeIiLsMKtZFaDb4zN4lHVvTsWN2Ps92Z9yMXvmW59SClKoy+YkeOcdI/inW9t1jajb4pJ+di+xYTBdZSZJGdnEvw4LatYDt/Y6q8kZAMrAEnzcC1sXZVxtTs8Gnc0Uni/1GSjByJuEBn4lMVa/Mjbogqkr/gOc8wWf7sdDHlcbRbPAZoHAuROIleCPuHQsCywM+U7Ej32wVYO3Qz6C/EDrVO2jMfp83G3zel/YJM5iyhxt14CbTzRl37HXAqsqC5a3Gu6YvUlN6TJh5NQ18D2fCCf1GLQ6MB/zQeZlhwDAK3bkfJ8/as7Dfq+W5ZoJP7oa1TM69ac+JCJNxI4kH/Njv+THFcixo4/hYbdIwaSb4zOwi8HSQnEjRiOTToJm6wG8I3WuuDaBXyx9hIB3SMGkK+KbIgiamw046AZXIhQwu8A+JiPviu1hAUtVfNteINZTR5GCbBT7zHek1EnTnyi32fZeIOAo4FHiqyu+zna0iwrhsrOKqVPy8iAxVqcTaiWmsWeDTduYN2syT5EIwo5icOS8MrZrm5520q7r86yLCyFesoqrvArDB/PgML6JumDQLfAa8nUq9cs6/4VCejerl8l5+k/dTU1p2EJKmyuVBk88ZubYrqH33500B3+mQefkiUtc9+a6d/1kRcQoTosy56rOq6lyawau5mPnQMGkq+HHMwkUpN+RMVlXmZTLp6zsiwkr1hkkrgu/cKMKbxZmNEKuYnH/eFEs2842sNIwSA44ymFYEv1xlzjT1enLj/UBSVeZsMm5LbBx8aBzwRnDm+8QmrQg+gzHM4Ql9V38UtFSVNxD6XXHAi1MZPYuF72lF8Fm9SOdq2HXoUQCu9qyqMnXQ/St33scvFxGWFNUtrQg+Ax0MeND9Z15lrKKqPO/97kXjjr9QRBjpqltaEXySXkyA5X2VrJONVVSVuaPMGSr/Vi8AXvTBSy02Gwuo7qIIZ8CtCP7fmpxO/oBxtdzMWBelEY21IvhuhRg7n98IkP3abEXw+QsV/J1Bnr9kIB+Py/poJvDsqxXBd65FL/+ag8seZ2EDr2VhXVhL/OhlK4LPMVMJsnLQT5jXyQvsxrS0HPhE05NVVglg/kpdxWsdx9JqtCT4ZgGYhkKA3b9Bzo+uEhHerTnmpWXBNwvAEqGbAfD31XnM8BfkYv3dk0auYEuD30hgmtH2OPjNQNmnj58D4HoW5VYDLd0AAAAASUVORK5CYII="
@@ -22,24 +25,64 @@ func (im ImageFormatter) CriticalFail(text string) {
 }
 
 func (im ImageFormatter) Progress(output *types.Message) {
-	fmt.Println("**** " + output.Name + " ****")
+	fmt.Println("**** " + title(output) + " ****")
 	fmt.Println()
 	fmt.Println("✅👍✅👍✅👍✅👍✅")
 	fmt.Println()
+	im.printWarnings(output)
+	printScore(output.Score)
 }
 func (im ImageFormatter) Fail(output *types.Message) {
-	fmt.Println("**** " + output.Name + " ****")
+	fmt.Println("**** " + title(output) + " ****")
 	fmt.Println()
 	im.printImage(failure)
 
 	fmt.Println()
 	fmt.Println(prettify(output.Errors))
+	im.printWarnings(output)
+	printScore(output.Score)
+}
+
+// Score shows the score of the whole input
+func (im ImageFormatter) Score(score int) {
+	fmt.Println("**** Total ****")
+	fmt.Println()
+	printScore(score)
+}
+
+func printScore(score int) {
+	fmt.Printf("🏆 HA score: %d/100\n\n", score)
+}
+
+func (im ImageFormatter) printWarnings(output *types.Message) {
+	if len(output.Warnings) == 0 {
+		return
+	}
+	result := ""
+	for _, msg := range output.Warnings {
+		result = result + "⚠️  " + msg + "\n"
+	}
+	fmt.Println(result)
 }
 
 func (im ImageFormatter) printImage(image string) {
+	if im.HideImages {
+		return
+	}
 	fmt.Printf("\033]1337;File=inline=1;preserveAspectRatio=1:%s\a\n", image)
 }
 
+func title(output *types.Message) string {
+	result := output.Name
+	if output.Kind != "" {
+		result = output.Kind + " " + result
+	}
+	if output.Source != "" {
+		result = result + " (" + output.Source + ")"
+	}
+	return result
+}
+
 func prettify(errors []string) string {
 	result := ""
 
@@ -0,0 +1,121 @@
+package gitdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// File is a manifest in the base revision or in the working tree. Contents are nil when the file does not exist in that version
+type File struct {
+	// Path is relative to the current directory
+	Path    string
+	Base    []byte
+	Current []byte
+	// Changed is true for files in paths that differ between the versions
+	Changed bool
+}
+
+// Files returns all files in the current directory that match, from the merge base of base and HEAD and from the working tree,
+// so that changed files can be analyzed together with the rest. Only changes made on the current branch mark files as changed.
+// Untracked files are included, ignored files are not.
+func Files(base string, paths []string, match func(path string) bool) ([]File, error) {
+	mergeBase, err := git("merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	revision := strings.TrimSpace(string(mergeBase))
+	different, err := changedNames(revision, nil)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := changedNames(revision, paths)
+	if err != nil {
+		return nil, err
+	}
+	tracked, err := git("ls-files", "--cached")
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range different {
+		names[name] = true
+	}
+	for _, name := range strings.Split(string(tracked), "\n") {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	files := []File{}
+	for name := range names {
+		if !match(name) {
+			continue
+		}
+		file := File{Path: name, Changed: selected[name]}
+		if different[name] {
+			file.Base, file.Current, err = versions(revision, name)
+		} else {
+			// Files that did not change are the same in both versions
+			file.Current, err = ioutil.ReadFile(name)
+			file.Base = file.Current
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// changedNames returns files in paths that differ between the working tree and revision, including untracked files
+func changedNames(revision string, paths []string) (map[string]bool, error) {
+	changed, err := git(append([]string{"diff", "--name-only", "--relative", revision, "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(append([]string{"ls-files", "--others", "--exclude-standard", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, name := range strings.Split(string(changed)+string(untracked), "\n") {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	return names, nil
+}
+
+// versions reads the file in revision and in the working tree
+func versions(revision, name string) ([]byte, []byte, error) {
+	var base []byte
+	// Revision path must start with ./ to be relative to the current directory
+	if _, err := git("cat-file", "-e", revision+":./"+name); err == nil {
+		base, err = git("show", revision+":./"+name)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	current, err := ioutil.ReadFile(name)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	return base, current, nil
+}
+
+func git(args ...string) ([]byte, error) {
+	stderr := &bytes.Buffer{}
+	command := exec.Command("git", args...)
+	command.Stderr = stderr
+	out, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
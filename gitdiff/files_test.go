@@ -0,0 +1,90 @@
+package gitdiff_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/gitdiff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Files", func() {
+	var dir, cwd string
+
+	git := func(args ...string) {
+		command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		command.Dir = dir
+		out, err := command.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+	}
+	write := func(name, contents string) {
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		cwd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "gitdiff")
+		Expect(err).NotTo(HaveOccurred())
+
+		git("init", "-q")
+		write("deploy/api.yml", "replicas: 3")
+		write("deploy/web.yml", "replicas: 2")
+		write("deploy/old.yml", "replicas: 2")
+		write("deploy/same.yml", "replicas: 5")
+		git("add", "-A")
+		git("commit", "-q", "-m", "base")
+		git("tag", "base")
+
+		write("deploy/api.yml", "replicas: 1")
+		git("commit", "-q", "-am", "change")
+		write("deploy/web.yml", "replicas: 4")
+		write("deploy/new.yml", "replicas: 2")
+		Expect(os.Remove(filepath.Join(dir, "deploy", "old.yml"))).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "deploy"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(cwd)).To(Succeed())
+		os.RemoveAll(dir)
+	})
+
+	all := func(string) bool { return true }
+
+	It("returns committed, uncommitted, untracked and removed files as changed", func() {
+		files, err := gitdiff.Files("base", nil, all)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal([]gitdiff.File{
+			{Path: "api.yml", Base: []byte("replicas: 3"), Current: []byte("replicas: 1"), Changed: true},
+			{Path: "new.yml", Current: []byte("replicas: 2"), Changed: true},
+			{Path: "old.yml", Base: []byte("replicas: 2"), Changed: true},
+			{Path: "same.yml", Base: []byte("replicas: 5"), Current: []byte("replicas: 5")},
+			{Path: "web.yml", Base: []byte("replicas: 2"), Current: []byte("replicas: 4"), Changed: true},
+		}))
+	})
+
+	It("marks only files in paths as changed", func() {
+		files, err := gitdiff.Files("base", []string{"web.yml"}, all)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(5))
+		for _, file := range files {
+			Expect(file.Changed).To(Equal(file.Path == "web.yml"), file.Path)
+		}
+	})
+
+	It("returns only matching files", func() {
+		files, err := gitdiff.Files("base", nil, func(path string) bool { return path == "same.yml" })
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal([]gitdiff.File{{Path: "same.yml", Base: []byte("replicas: 5"), Current: []byte("replicas: 5")}}))
+	})
+
+	It("fails for unknown base", func() {
+		_, err := gitdiff.Files("branch_that_should_not_exist", nil, all)
+		Expect(err).To(MatchError(ContainSubstring("git merge-base failed")))
+	})
+})
@@ -0,0 +1,13 @@
+package gitdiff_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGitdiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gitdiff Suite")
+}
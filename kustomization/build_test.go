@@ -0,0 +1,30 @@
+package kustomization_test
+
+import (
+	"path/filepath"
+
+	"github.com/alex-slynko/haornot/kustomization"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build", func() {
+	overlays := filepath.Join("..", "fixtures", "kustomize", "overlays")
+
+	It("builds every overlay separately", func() {
+		dev, err := kustomization.Build(filepath.Join(overlays, "dev"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(dev)).To(ContainSubstring("replicas: 1"))
+		Expect(string(dev)).To(ContainSubstring("name: dev-nginx"))
+
+		prod, err := kustomization.Build(filepath.Join(overlays, "prod"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(prod)).To(ContainSubstring("replicas: 3"))
+		Expect(string(prod)).To(ContainSubstring("name: prod-nginx"))
+	})
+
+	It("returns error when kustomization is missing", func() {
+		_, err := kustomization.Build(filepath.Join(overlays, "overlay_that_should_not_exist"))
+		Expect(err).To(HaveOccurred())
+	})
+})
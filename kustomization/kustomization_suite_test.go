@@ -0,0 +1,13 @@
+package kustomization_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestKustomization(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kustomization Suite")
+}
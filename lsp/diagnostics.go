@@ -0,0 +1,179 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/types"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const source = "haornot"
+
+// document is a YAML document between --- separators
+type document struct {
+	// start and end are lines of the document in the file, end is exclusive
+	start int
+	end   int
+	text  string
+}
+
+// quickFix is a diagnostic with an edit that resolves it
+type quickFix struct {
+	diagnostic diagnostic
+	edit       textEdit
+}
+
+// analyzeText analyzes all documents in the file together, so services are matched with workloads from the same file
+func analyzeText(text string, options analyzer.Options) ([]diagnostic, []quickFix) {
+	lines := strings.Split(text, "\n")
+	diagnostics := []diagnostic{}
+	objects := []runtime.Object{}
+	documents := map[string]document{}
+	for _, d := range splitDocuments(lines) {
+		obj, err := analyzer.Decode([]byte(d.text))
+		if err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(lineRange(lines, d.start), errorSeverity, err.Error()))
+			continue
+		}
+		documents[key(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = d
+		objects = append(objects, obj)
+	}
+
+	result, err := analyzer.AnalyzeObjects(objects, options)
+	if err != nil {
+		return append(diagnostics, newDiagnostic(lineRange(lines, 0), errorSeverity, err.Error())), nil
+	}
+	for _, err := range result.Failures {
+		line := 0
+		if objectErr, ok := err.(*analyzer.ObjectError); ok {
+			if d, ok := documents[key(objectErr.Kind, objectErr.Namespace, objectErr.Name)]; ok {
+				line = d.line([]string{"metadata", "name"})
+			}
+		}
+		diagnostics = append(diagnostics, newDiagnostic(lineRange(lines, line), errorSeverity, err.Error()))
+	}
+
+	fixes := []quickFix{}
+	for _, messages := range [][]*types.Message{result.Workloads, result.Services, result.Others} {
+		for _, msg := range messages {
+			d, ok := documents[key(msg.Kind, msg.Namespace, msg.Name)]
+			if !ok {
+				continue
+			}
+			fixable := map[string]types.Fix{}
+			for _, fix := range msg.Fixes {
+				fixable[fix.Finding] = fix
+			}
+			for _, finding := range msg.Errors {
+				fix, ok := fixable[finding]
+				line := d.line([]string{"metadata", "name"})
+				if ok {
+					line = d.line(fix.Path)
+				}
+				diag := newDiagnostic(lineRange(lines, line), errorSeverity, finding)
+				diagnostics = append(diagnostics, diag)
+				if !ok {
+					continue
+				}
+				if edit, err := d.apply(fix, lines); err == nil {
+					fixes = append(fixes, quickFix{diagnostic: diag, edit: edit})
+				}
+			}
+			for _, finding := range msg.Warnings {
+				diagnostics = append(diagnostics, newDiagnostic(lineRange(lines, d.line([]string{"metadata", "name"})), warningSeverity, finding))
+			}
+		}
+	}
+	return diagnostics, fixes
+}
+
+func splitDocuments(lines []string) []document {
+	documents := []document{}
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !strings.HasPrefix(lines[i], "---") {
+			continue
+		}
+		d := document{start: start, end: i, text: strings.Join(lines[start:i], "\n")}
+		if !isEmpty(lines[start:i]) {
+			documents = append(documents, d)
+		}
+		start = i + 1
+	}
+	return documents
+}
+
+// isEmpty is true for documents with only comments, e.g. while a new document is typed
+func isEmpty(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// line returns the line of the field or of its closest parent, or the first line of the document
+func (d document) line(path []string) int {
+	node, err := yaml.Parse(d.text)
+	if err != nil {
+		return d.start
+	}
+	for i := len(path); i > 0; i-- {
+		field, err := node.Pipe(yaml.Lookup(path[:i]...))
+		if err == nil && field != nil {
+			return d.start + field.YNode().Line - 1
+		}
+	}
+	return d.start
+}
+
+// apply returns an edit that replaces the document with the fixed one. Comments are kept
+func (d document) apply(fix types.Fix, lines []string) (textEdit, error) {
+	node, err := yaml.Parse(d.text)
+	if err != nil {
+		return textEdit{}, err
+	}
+	parent, err := node.Pipe(yaml.LookupCreate(yaml.MappingNode, fix.Path[:len(fix.Path)-1]...))
+	if err != nil {
+		return textEdit{}, err
+	}
+	if err := parent.PipeE(yaml.SetField(fix.Path[len(fix.Path)-1], yaml.NewScalarRNode(fix.Value))); err != nil {
+		return textEdit{}, err
+	}
+	text, err := node.String()
+	if err != nil {
+		return textEdit{}, err
+	}
+
+	end := position{Line: d.end, Character: 0}
+	if d.end == len(lines) {
+		// The last document has no separator after it
+		end = position{Line: d.end - 1, Character: utf16Length(lines[d.end-1])}
+		if !strings.HasSuffix(d.text, "\n") {
+			text = strings.TrimSuffix(text, "\n")
+		}
+	}
+	return textEdit{Range: textRange{Start: position{Line: d.start}, End: end}, NewText: text}, nil
+}
+
+func key(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func lineRange(lines []string, line int) textRange {
+	return textRange{Start: position{Line: line}, End: position{Line: line, Character: utf16Length(lines[line])}}
+}
+
+// utf16Length is the length of the line in UTF-16 code units, which LSP positions count
+func utf16Length(line string) int {
+	return len(utf16.Encode([]rune(line)))
+}
+
+func newDiagnostic(r textRange, severity int, message string) diagnostic {
+	return diagnostic{Range: r, Severity: severity, Source: source, Message: message}
+}
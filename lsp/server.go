@@ -0,0 +1,119 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alex-slynko/haornot/analyzer"
+)
+
+type server struct {
+	out     io.Writer
+	options analyzer.Options
+	// fixes are quick fixes for the last version of every open document
+	fixes map[string][]quickFix
+}
+
+// Serve runs language server on in and out, usually stdin and stdout, until the client sends exit.
+// Documents are analyzed when they are opened or changed, and findings are published as diagnostics.
+func Serve(in io.Reader, out io.Writer, options analyzer.Options) error {
+	s := &server{out: out, options: options, fixes: map[string][]quickFix{}}
+	r := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) handle(msg *message) error {
+	switch msg.Method {
+	case "initialize":
+		return s.reply(msg, map[string]interface{}{
+			"capabilities": map[string]interface{}{"textDocumentSync": fullSync, "codeActionProvider": true},
+			"serverInfo":   map[string]string{"name": source},
+		})
+	case "shutdown":
+		return s.reply(msg, nil)
+	case "textDocument/didOpen":
+		params := didOpenParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.fail(msg, invalidParams, err)
+		}
+		return s.publish(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		params := didChangeParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.fail(msg, invalidParams, err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// Full sync sends the whole document in the last change
+		return s.publish(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		params := didOpenParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.fail(msg, invalidParams, err)
+		}
+		delete(s.fixes, params.TextDocument.URI)
+		return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/codeAction":
+		params := codeActionParams{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.fail(msg, invalidParams, err)
+		}
+		return s.reply(msg, s.codeActions(params))
+	}
+	// Notifications that are not supported, like initialized, are ignored
+	return s.fail(msg, methodNotFound, fmt.Errorf("method %s is not supported", msg.Method))
+}
+
+func (s *server) publish(uri, text string) error {
+	diagnostics, fixes := analyzeText(text, s.options)
+	s.fixes[uri] = fixes
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// codeActions returns quick fixes for diagnostics on the lines of the range
+func (s *server) codeActions(params codeActionParams) []codeAction {
+	actions := []codeAction{}
+	for _, fix := range s.fixes[params.TextDocument.URI] {
+		line := fix.diagnostic.Range.Start.Line
+		if line < params.Range.Start.Line || line > params.Range.End.Line {
+			continue
+		}
+		action := codeAction{Title: "Fix: " + fix.diagnostic.Message, Kind: "quickfix", Diagnostics: []diagnostic{fix.diagnostic}}
+		action.Edit.Changes = map[string][]textEdit{params.TextDocument.URI: {fix.edit}}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func (s *server) reply(msg *message, result interface{}) error {
+	return writeMessage(s.out, response{JSONRPC: "2.0", ID: msg.ID, Result: result})
+}
+
+// fail replies with error to requests. Notifications do not have replies
+func (s *server) fail(msg *message, code int, err error) error {
+	if msg.ID == nil {
+		return nil
+	}
+	return writeMessage(s.out, errorResponse{JSONRPC: "2.0", ID: msg.ID, Error: responseError{Code: code, Message: err.Error()}})
+}
+
+func (s *server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -0,0 +1,194 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/lsp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const uri = "file:///deploy.yml"
+
+const manifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+# Source: api/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+`
+
+type received struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+	Params struct {
+		URI         string `json:"uri"`
+		Diagnostics []struct {
+			Range struct {
+				Start struct{ Line int } `json:"start"`
+				End   struct {
+					Line      int
+					Character int
+				} `json:"end"`
+			} `json:"range"`
+			Severity int    `json:"severity"`
+			Message  string `json:"message"`
+		} `json:"diagnostics"`
+	} `json:"params"`
+}
+
+var _ = Describe("Serve", func() {
+	var input *bytes.Buffer
+
+	send := func(id int, method string, params interface{}) {
+		msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+		if id > 0 {
+			msg["id"] = id
+		}
+		body, err := json.Marshal(msg)
+		Expect(err).NotTo(HaveOccurred())
+		fmt.Fprintf(input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+
+	serve := func() []received {
+		send(0, "exit", nil)
+		output := &bytes.Buffer{}
+		Expect(lsp.Serve(input, output, analyzer.Options{})).To(Succeed())
+
+		messages := []received{}
+		r := bufio.NewReader(output)
+		for {
+			headers, err := textproto.NewReader(r).ReadMIMEHeader()
+			if err == io.EOF {
+				return messages
+			}
+			Expect(err).NotTo(HaveOccurred())
+			length, err := strconv.Atoi(headers.Get("Content-Length"))
+			Expect(err).NotTo(HaveOccurred())
+			body := make([]byte, length)
+			_, err = io.ReadFull(r, body)
+			Expect(err).NotTo(HaveOccurred())
+			msg := received{}
+			Expect(json.Unmarshal(body, &msg)).To(Succeed())
+			messages = append(messages, msg)
+		}
+	}
+
+	BeforeEach(func() {
+		input = &bytes.Buffer{}
+		send(1, "initialize", map[string]interface{}{})
+		send(0, "initialized", map[string]interface{}{})
+		send(0, "textDocument/didOpen", map[string]interface{}{"textDocument": map[string]string{"uri": uri, "text": manifest}})
+	})
+
+	It("publishes findings at their positions", func() {
+		messages := serve()
+		Expect(messages).To(HaveLen(2))
+		Expect(string(messages[0].Result)).To(ContainSubstring(`"codeActionProvider":true`))
+
+		Expect(messages[1].Method).To(Equal("textDocument/publishDiagnostics"))
+		Expect(messages[1].Params.URI).To(Equal(uri))
+		Expect(messages[1].Params.Diagnostics).To(HaveLen(1))
+		diagnostic := messages[1].Params.Diagnostics[0]
+		Expect(diagnostic.Message).To(Equal("At least 2 replicas required for deployment"))
+		Expect(diagnostic.Severity).To(Equal(1))
+		Expect(diagnostic.Range.Start.Line).To(Equal(11))
+	})
+
+	It("analyzes document again on change", func() {
+		send(0, "textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]string{"uri": uri},
+			"contentChanges": []map[string]string{{"text": "kind: Deployment\nmetadata:\n  name: api\nspec:\n  replicas: 3\n  template:\n    spec:\n      containers:\n      - name: api\n        image: api\n"}},
+		})
+		messages := serve()
+		Expect(messages).To(HaveLen(3))
+		Expect(messages[2].Params.Diagnostics).NotTo(BeEmpty())
+		for _, diagnostic := range messages[2].Params.Diagnostics {
+			Expect(diagnostic.Message).NotTo(ContainSubstring("replicas required"))
+			Expect(diagnostic.Range.Start.Line).To(Equal(2))
+		}
+	})
+
+	It("counts characters in UTF-16 code units", func() {
+		send(0, "textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]string{"uri": uri},
+			"contentChanges": []map[string]string{{"text": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  replicas: 1 # 🚀 ünï\n  template:\n    spec:\n      containers:\n      - name: api\n        image: api:1.0\n"}},
+		})
+		messages := serve()
+		Expect(messages).To(HaveLen(3))
+		Expect(messages[2].Params.Diagnostics).To(HaveLen(1))
+		Expect(messages[2].Params.Diagnostics[0].Range.End.Line).To(Equal(5))
+		Expect(messages[2].Params.Diagnostics[0].Range.End.Character).To(Equal(22))
+	})
+
+	It("reports objects that can not be analyzed at their document", func() {
+		send(0, "textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]string{"uri": uri},
+			"contentChanges": []map[string]string{{"text": manifest + "---\napiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: gear\n"}},
+		})
+		messages := serve()
+		Expect(messages).To(HaveLen(3))
+		lines := map[string]int{}
+		for _, diagnostic := range messages[2].Params.Diagnostics {
+			lines[diagnostic.Message] = diagnostic.Range.Start.Line
+		}
+		Expect(lines).To(HaveKeyWithValue(ContainSubstring("Widget"), 21))
+	})
+
+	It("offers quick fix that keeps comments", func() {
+		send(2, "textDocument/codeAction", map[string]interface{}{
+			"textDocument": map[string]string{"uri": uri},
+			"range":        map[string]interface{}{"start": map[string]int{"line": 11}, "end": map[string]int{"line": 11}},
+			"context":      map[string]interface{}{"diagnostics": []interface{}{}},
+		})
+		messages := serve()
+		Expect(messages).To(HaveLen(3))
+		actions := []struct {
+			Title string
+			Edit  struct {
+				Changes map[string][]struct {
+					Range struct {
+						Start struct{ Line int }
+					}
+					NewText string
+				}
+			}
+		}{}
+		Expect(json.Unmarshal(messages[2].Result, &actions)).To(Succeed())
+		Expect(actions).To(HaveLen(1))
+		Expect(actions[0].Title).To(Equal("Fix: At least 2 replicas required for deployment"))
+		edit := actions[0].Edit.Changes[uri][0]
+		Expect(edit.Range.Start.Line).To(Equal(5))
+		Expect(edit.NewText).To(HavePrefix("# Source: api/templates/deployment.yaml\n"))
+		Expect(edit.NewText).To(ContainSubstring("replicas: 2\n"))
+	})
+
+	It("replies with error to unknown requests", func() {
+		send(2, "textDocument/hover", map[string]interface{}{})
+		messages := serve()
+		Expect(messages).To(HaveLen(3))
+		Expect(messages[2].Error.Code).To(Equal(-32601))
+	})
+})
@@ -2,65 +2,276 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/chart"
 	"github.com/alex-slynko/haornot/formatter"
+	"github.com/alex-slynko/haornot/kustomization"
+	"github.com/alex-slynko/haornot/lsp"
 	"github.com/alex-slynko/haornot/types"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-var hasErrors bool
+// subcommands are dispatched before flags are parsed, so every subcommand has own flags after its name
+var subcommands = map[string]func(args []string){
+	"chart":     runChart,
+	"kustomize": runKustomize,
+	"diff":      runDiff,
+	"lsp":       runLSP,
+}
 
 func main() {
-	if len(os.Args) < 2 {
+	if strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") == pluginName {
+		runPlugin(os.Args[1:])
+		return
+	}
+	if len(os.Args) > 1 {
+		// Files named like subcommands are still analyzed. Directories are only watched, and --watch goes before them
+		if run, ok := subcommands[os.Args[1]]; ok && !isFile(os.Args[1]) {
+			run(os.Args[2:])
+			return
+		}
+	}
+
+	options := addOptionFlags(flag.CommandLine)
+	minScore := addMinScoreFlag(flag.CommandLine)
+	watchMode := flag.Bool("watch", false, "Watch files and directories and analyze them again on every change")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
 		failWith("Spec file is required")
-		os.Exit(1)
 	}
-	contents, err := ioutil.ReadFile(os.Args[1])
 
+	if *watchMode {
+		if err := watch(flag.Args(), options.load(), *minScore); err != nil {
+			failWith(err.Error())
+		}
+		return
+	}
+
+	contents, err := ioutil.ReadFile(flag.Arg(0))
 	if err != nil {
 		failWith(err.Error())
-		os.Exit(1)
 	}
-	hasErrors = false
-	manifests := bytes.Split(contents, []byte("\n---"))
-	totalDeployments := 0
-	deploymentsWithoutErrors := 0
-	for _, manifest := range manifests {
-		output, err := analyzer.Analyze(manifest)
+	analyzeManifests(contents, options.load(), *minScore)
+}
 
-		if err == analyzer.ErrNotADeployment {
-			continue
-		}
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// optionFlags are flags that configure analysis, shared by all commands
+type optionFlags struct {
+	configPath  *string
+	profile     *string
+	policiesDir *string
+	workers     *int
+}
+
+func addOptionFlags(flags *flag.FlagSet) optionFlags {
+	return optionFlags{
+		configPath:  flags.String("config", "", "Path to config file"),
+		profile:     flags.String("profile", "", "Profile from config file used for resources that do not select own profile"),
+		policiesDir: flags.String("policies", "", "Directory with Rego policies: conftest deny and warn rules, or Gatekeeper violation rules"),
+		workers:     flags.Int("workers", 0, "Number of documents analyzed in parallel. Default is the number of CPUs"),
+	}
+}
+
+func (f optionFlags) load() analyzer.Options {
+	return loadOptions(*f.configPath, *f.profile, *f.policiesDir, *f.workers)
+}
+
+func addMinScoreFlag(flags *flag.FlagSet) *int {
+	return flags.Int("min-score", -1, "Minimal HA score from 0 to 100. When set, the score decides if the check passes instead of errors")
+}
+
+func runChart(args []string) {
+	flags := flag.NewFlagSet("chart", flag.ExitOnError)
+	valueFiles := stringsFlag{}
+	flags.Var(&valueFiles, "f", "Values file, can be specified multiple times")
+	options := addOptionFlags(flags)
+	minScore := addMinScoreFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		failWith("Chart directory is required")
+	}
+	// Allow values files after chart directory, like in helm
+	chartPath := flags.Arg(0)
+	flags.Parse(flags.Args()[1:])
+
+	contents, err := chart.Render(chartPath, valueFiles)
+	if err != nil {
+		failWith(err.Error())
+	}
+	analyzeManifests(contents, options.load(), *minScore)
+}
+
+func runKustomize(args []string) {
+	flags := flag.NewFlagSet("kustomize", flag.ExitOnError)
+	options := addOptionFlags(flags)
+	minScore := addMinScoreFlag(flags)
+	flags.Parse(args)
+
+	contents, overlayConfig, err := buildKustomization(flags.Args())
+	if err != nil {
+		failWith(err.Error())
+	}
+	if *options.configPath == "" {
+		*options.configPath = overlayConfig
+	}
+	analyzeManifests(contents, options.load(), *minScore)
+}
+
+func runLSP(args []string) {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	options := addOptionFlags(flags)
+	flags.Parse(args)
+
+	if err := lsp.Serve(os.Stdin, os.Stdout, options.load()); err != nil {
+		failWith(err.Error())
+	}
+}
+
+// analyzeManifests decodes and analyzes multi-document YAML and reports the result
+func analyzeManifests(contents []byte, options analyzer.Options, minScore int) {
+	objects, invalid := decodeManifests(contents, options.Workers)
+	analyze(objects, invalid, options, minScore)
+}
 
-		totalDeployments++
+func loadOptions(configPath, profile, policiesDir string, workers int) analyzer.Options {
+	var err error
+	config := analyzer.Config{}
+	if configPath != "" {
+		config, err = analyzer.LoadConfig(configPath)
 		if err != nil {
-			showError(err)
-			continue
+			failWith(err.Error())
 		}
-		showDeploymentMessage(output)
-		if len(output.Errors) == 0 {
-			deploymentsWithoutErrors++
+	}
+	var policies *analyzer.Policies
+	if policiesDir != "" {
+		policies, err = analyzer.LoadPolicies(policiesDir)
+		if err != nil {
+			failWith(err.Error())
 		}
 	}
+	return analyzer.Options{Config: config, Profile: profile, Policies: policies, Workers: workers}
+}
 
-	if totalDeployments == 0 {
-		failWith("only deployments can be analyzed")
+// analyze analyzes objects and reports the result
+func analyze(objects []runtime.Object, invalid []error, options analyzer.Options, minScore int) {
+	result, err := analyzer.AnalyzeObjects(objects, options)
+	if err != nil {
+		failWith(err.Error())
 	}
-	if !hasErrors {
-		success()
+	report(result, invalid, minScore, true)
+}
+
+// report shows the result and exits with error when the check fails
+func report(result *analyzer.Result, invalid []error, minScore int, requireWorkloads bool) {
+	passed, message := check(formatter.ImageFormatter{}, result, invalid, minScore, requireWorkloads)
+	if passed {
+		success(formatter.ImageFormatter{}, message)
+	} else if message != "" {
+		failWith(message)
 	} else {
 		os.Exit(1)
 	}
 }
 
-func success() {
-	formatter := formatter.ImageFormatter{}
-	formatter.Success()
+func decodeManifests(contents []byte, workers int) ([]runtime.Object, []error) {
+	decoded, errors := analyzer.DecodeManifests(bytes.Split(contents, []byte("\n---")), workers)
+	objects := []runtime.Object{}
+	invalid := []error{}
+	for i, err := range errors {
+		if err != nil {
+			invalid = append(invalid, err)
+			continue
+		}
+		objects = append(objects, decoded[i])
+	}
+	return objects, invalid
+}
+
+// check shows all messages with out and returns whether the input passes and the final message.
+// Input without workloads fails when requireWorkloads is set
+func check(out formatter.ImageFormatter, result *analyzer.Result, invalid []error, minScore int, requireWorkloads bool) (bool, string) {
+	for _, err := range invalid {
+		out.CriticalFail(err.Error())
+	}
+	for _, err := range result.Failures {
+		out.CriticalFail(err.Error())
+	}
+	for _, output := range result.Workloads {
+		showMessage(out, output)
+	}
+	for _, output := range result.Services {
+		showMessage(out, output)
+	}
+	for _, output := range result.Others {
+		showMessage(out, output)
+	}
+
+	if requireWorkloads && len(result.Workloads)+len(result.Failures)+len(invalid) == 0 {
+		return false, "only deployments, statefulsets, daemonsets, jobs, cronjobs, pods and replicasets can be analyzed"
+	}
+	// Documents that could not be decoded score 0, same as objects that could not be analyzed
+	scored := *result
+	scored.Failures = append(append([]error{}, invalid...), result.Failures...)
+	score := scored.Score()
+	out.Score(score)
+	if minScore >= 0 {
+		if score < minScore {
+			return false, fmt.Sprintf("HA score %d is lower than required %d", score, minScore)
+		}
+		return true, fmt.Sprintf("HA score %d satisfies required %d", score, minScore)
+	}
+	if result.HasErrors() || len(invalid) > 0 {
+		return false, ""
+	}
+	return true, "Your spec file satifies all checks"
+}
+
+// Overlays can have own config, so every environment is checked with its own rules
+const overlayConfigFile = "haornot.yml"
+
+// buildKustomization builds overlay and returns path to its config, or "" when overlay does not have one
+func buildKustomization(args []string) ([]byte, string, error) {
+	if len(args) < 1 {
+		return nil, "", fmt.Errorf("Kustomization directory is required")
+	}
+	contents, err := kustomization.Build(args[0])
+	if err != nil {
+		return nil, "", err
+	}
+	overlayConfig := filepath.Join(args[0], overlayConfigFile)
+	if _, err := os.Stat(overlayConfig); err != nil {
+		return contents, "", nil
+	}
+	return contents, overlayConfig, nil
+}
+
+type stringsFlag []string
+
+func (v *stringsFlag) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *stringsFlag) Set(value string) error {
+	*v = append(*v, value)
+	return nil
+}
+
+func success(out formatter.ImageFormatter, message string) {
+	out.Success()
 	fmt.Println()
-	fmt.Println("😸 Your spec file satifies all checks 😸")
+	fmt.Println("😸 " + message + " 😸")
 }
 
 func failWith(message string) {
@@ -69,17 +280,10 @@ func failWith(message string) {
 	os.Exit(1)
 }
 
-func showError(err error) {
-	formatter := formatter.ImageFormatter{}
-	formatter.CriticalFail(err.Error())
-}
-
-func showDeploymentMessage(em *types.Message) {
-	formatter := formatter.ImageFormatter{}
+func showMessage(out formatter.ImageFormatter, em *types.Message) {
 	if len(em.Errors) > 0 {
-		formatter.Fail(em)
-		hasErrors = true
+		out.Fail(em)
 	} else {
-		formatter.Progress(em)
+		out.Progress(em)
 	}
 }
@@ -1,27 +1,32 @@
 package main_test
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 )
 
 var _ = Describe("Haornot", func() {
 	var (
 		spec    string
+		flags   []string
 		session *gexec.Session
 	)
 
 	BeforeEach(func() {
 		spec = path.Join(cwd, "fixtures", "nginx.yml")
+		flags = []string{}
 	})
 
 	JustBeforeEach(func() {
 		var err error
-		command := exec.Command(pathToCLI, spec)
+		command := exec.Command(pathToCLI, append(flags, spec)...)
 		session, err = gexec.Start(command, GinkgoWriter, GinkgoWriter)
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -52,6 +57,280 @@ var _ = Describe("Haornot", func() {
 		})
 	})
 
+	Context("when one of the documents can not be analyzed", func() {
+		BeforeEach(func() {
+			spec = path.Join(cwd, "fixtures", "partly_invalid_nginx.yml")
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("replicas"))
+			Expect(session.Out).NotTo(gbytes.Say("satifies all checks"))
+		})
+	})
+
+	Context("when service does not match any deployment", func() {
+		BeforeEach(func() {
+			spec = path.Join(cwd, "fixtures", "unmatched_service.yml")
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Service web"))
+		})
+	})
+
+	Context("when config enables optional checks", func() {
+		BeforeEach(func() {
+			flags = []string{"--config", path.Join(cwd, "fixtures", "guaranteed_qos_config.yml")}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Guaranteed QoS"))
+		})
+	})
+
+	Context("when profile is selected", func() {
+		BeforeEach(func() {
+			flags = []string{"--config", path.Join(cwd, "fixtures", "profiles_config.yml"), "--profile", "prod"}
+		})
+
+		It("uses rules from the profile", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("spread across zones"))
+		})
+	})
+
+	Context("when profile is not in config", func() {
+		BeforeEach(func() {
+			flags = []string{"--config", path.Join(cwd, "fixtures", "profiles_config.yml"), "--profile", "staging"}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("unknown profile staging"))
+		})
+	})
+
+	Context("when config has custom rules", func() {
+		BeforeEach(func() {
+			flags = []string{"--config", path.Join(cwd, "fixtures", "custom_rules_config.yml")}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Deployment nginx does not have team label"))
+		})
+	})
+
+	Context("when policies are passed", func() {
+		BeforeEach(func() {
+			flags = []string{"--policies", path.Join(cwd, "fixtures", "policies")}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Deployment nginx does not have team label"))
+		})
+	})
+
+	Context("when minimal score is set", func() {
+		BeforeEach(func() {
+			spec = path.Join(cwd, "fixtures", "replicated_nginx.yml")
+		})
+
+		Context("and the score is lower", func() {
+			BeforeEach(func() {
+				flags = []string{"--min-score", "90"}
+			})
+
+			It("exits with error", func() {
+				Eventually(session).Should(gexec.Exit())
+				Expect(session.ExitCode()).NotTo(Equal(0))
+				Expect(session.Out).To(gbytes.Say("HA score \\d+ is lower than required 90"))
+			})
+		})
+
+		Context("and a document can not be analyzed", func() {
+			BeforeEach(func() {
+				spec = path.Join(cwd, "fixtures", "invalid_replicas.yml")
+				flags = []string{"--min-score", "80"}
+			})
+
+			It("counts it as 0 and exits with error", func() {
+				Eventually(session).Should(gexec.Exit())
+				Expect(session.ExitCode()).NotTo(Equal(0))
+				Expect(session.Out).To(gbytes.Say("HA score 0 is lower than required 80"))
+			})
+		})
+
+		Context("and the score is higher", func() {
+			BeforeEach(func() {
+				flags = []string{"--min-score", "10"}
+			})
+
+			It("passes even with errors", func() {
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say("HA score: \\d+/100"))
+			})
+		})
+	})
+
+	Context("when watching directory", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "haornot")
+			Expect(err).NotTo(HaveOccurred())
+			copyFixture("bad_nginx.yml", path.Join(dir, "nginx.yml"))
+
+			flags = []string{"--watch"}
+			spec = dir
+		})
+
+		AfterEach(func() {
+			session.Kill()
+			os.RemoveAll(dir)
+		})
+
+		It("analyzes files again when they change", func() {
+			Eventually(session.Out).Should(gbytes.Say("Some checks fail"))
+			Consistently(session).ShouldNot(gexec.Exit())
+
+			copyFixture("nginx.yml", path.Join(dir, "nginx.yml"))
+			Eventually(session.Out).Should(gbytes.Say("Your spec file satifies all checks"))
+		})
+	})
+
+	Context("when config file is missing", func() {
+		BeforeEach(func() {
+			flags = []string{"--config", path.Join(cwd, "fixtures", "file_that_should_not_exist")}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+		})
+	})
+
+	Context("when chart is passed", func() {
+		BeforeEach(func() {
+			spec = path.Join(cwd, "fixtures", "chart")
+			flags = []string{"chart"}
+		})
+
+		It("exits with error for default values", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("nginx/templates/deployment.yaml"))
+		})
+
+		Context("with values file", func() {
+			BeforeEach(func() {
+				flags = []string{"chart", "-f", path.Join(cwd, "fixtures", "chart", "values-prod.yaml")}
+			})
+
+			It("exits with 0 status code", func() {
+				Eventually(session).Should(gexec.Exit(0))
+			})
+		})
+	})
+
+	Context("when kustomization is passed", func() {
+		BeforeEach(func() {
+			flags = []string{"kustomize"}
+		})
+
+		Context("for overlay with not enough replicas", func() {
+			BeforeEach(func() {
+				spec = path.Join(cwd, "fixtures", "kustomize", "overlays", "dev")
+			})
+
+			It("exits with error", func() {
+				Eventually(session).Should(gexec.Exit())
+				Expect(session.ExitCode()).NotTo(Equal(0))
+				Expect(session.Out).To(gbytes.Say("Deployment dev-nginx"))
+			})
+		})
+
+		Context("with flags after subcommand", func() {
+			BeforeEach(func() {
+				flags = []string{"kustomize", "--min-score", "0"}
+				spec = path.Join(cwd, "fixtures", "kustomize", "overlays", "dev")
+			})
+
+			It("uses the flags", func() {
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say("HA score \\d+ satisfies required 0"))
+			})
+		})
+
+		Context("for overlay that satisfies its own config", func() {
+			BeforeEach(func() {
+				spec = path.Join(cwd, "fixtures", "kustomize", "overlays", "prod")
+			})
+
+			It("exits with 0 status code", func() {
+				Eventually(session).Should(gexec.Exit(0))
+			})
+		})
+
+		Context("for overlay that does not satisfy its own config", func() {
+			BeforeEach(func() {
+				spec = path.Join(cwd, "fixtures", "kustomize", "overlays", "staging")
+			})
+
+			It("exits with error", func() {
+				Eventually(session).Should(gexec.Exit())
+				Expect(session.ExitCode()).NotTo(Equal(0))
+				Expect(session.Out).To(gbytes.Say("Guaranteed QoS"))
+			})
+		})
+	})
+
+	Context("when file is named like a subcommand", func() {
+		It("analyzes the file", func() {
+			dir, err := ioutil.TempDir("", "haornot")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			copyFixture("bad_nginx.yml", path.Join(dir, "diff"))
+
+			command := exec.Command(pathToCLI, "diff")
+			command.Dir = dir
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Deployment nginx"))
+		})
+	})
+
+	Context("when directory is named like a subcommand", func() {
+		It("can be watched", func() {
+			dir, err := ioutil.TempDir("", "haornot")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(os.Mkdir(path.Join(dir, "chart"), 0755)).To(Succeed())
+			copyFixture("nginx.yml", path.Join(dir, "chart", "nginx.yml"))
+
+			command := exec.Command(pathToCLI, "--watch", "chart")
+			command.Dir = dir
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			defer session.Kill()
+			Eventually(session.Out).Should(gbytes.Say("Watching 1 files"))
+		})
+	})
+
 	Context("when no spec is passed", func() {
 		It("exists with error", func() {
 			command := exec.Command(pathToCLI)
@@ -62,3 +341,9 @@ var _ = Describe("Haornot", func() {
 		})
 	})
 })
+
+func copyFixture(name, destination string) {
+	contents, err := ioutil.ReadFile(path.Join(cwd, "fixtures", name))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(ioutil.WriteFile(destination, contents, 0644)).To(Succeed())
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/alex-slynko/haornot/cluster"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// kubectl runs binaries named kubectl-<plugin> for `kubectl <plugin>` commands
+const pluginName = "kubectl-haornot"
+
+// runPlugin analyzes files and live objects with kubectl flags, e.g.
+// `kubectl haornot -f deploy.yaml` or `kubectl haornot deployment/api -n payments`
+func runPlugin(args []string) {
+	flags := flag.NewFlagSet(pluginName, flag.ExitOnError)
+	options := cluster.Options{}
+	filenames := stringsFlag{}
+	flags.StringVar(&options.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	flags.StringVar(&options.Context, "context", "", "The name of the kubeconfig context to use")
+	flags.StringVar(&options.Namespace, "namespace", "", "Namespace of live objects")
+	flags.StringVar(&options.Namespace, "n", "", "Namespace of live objects (shorthand)")
+	flags.StringVar(&options.Selector, "selector", "", "Label selector for files and live objects, e.g. app=api")
+	flags.StringVar(&options.Selector, "l", "", "Label selector (shorthand)")
+	flags.Var(&filenames, "filename", "File or directory with manifests, can be specified multiple times. - reads stdin")
+	flags.Var(&filenames, "f", "File or directory (shorthand)")
+	optionFlags := addOptionFlags(flags)
+	minScore := addMinScoreFlag(flags)
+
+	// Flags can follow resources, same as in kubectl
+	resources := []string{}
+	flags.Parse(args)
+	for flags.NArg() > 0 {
+		resources = append(resources, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	if len(filenames) == 0 && len(resources) == 0 {
+		failWith("Specify files with -f or resources, e.g. kubectl haornot deployment/api")
+	}
+
+	objects := []runtime.Object{}
+	invalid := []error{}
+	for _, filename := range filenames {
+		decoded, errors, err := readManifests(filename, *optionFlags.workers)
+		if err != nil {
+			failWith(err.Error())
+		}
+		selected, err := selectObjects(decoded, options.Selector)
+		if err != nil {
+			failWith(err.Error())
+		}
+		objects = append(objects, selected...)
+		invalid = append(invalid, errors...)
+	}
+	if len(resources) > 0 {
+		live, err := cluster.Get(options, resources)
+		if err != nil {
+			failWith(err.Error())
+		}
+		objects = append(objects, live...)
+	}
+
+	analyze(objects, invalid, optionFlags.load(), *minScore)
+}
+
+// readManifests reads file, stdin or manifests in directory, same as `kubectl apply -f`
+func readManifests(filename string, workers int) ([]runtime.Object, []error, error) {
+	if filename == "-" {
+		contents, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		objects, invalid := decodeManifests(contents, workers)
+		return objects, invalid, nil
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	paths := []string{filename}
+	if info.IsDir() {
+		files, err := ioutil.ReadDir(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = []string{}
+		for _, file := range files {
+			if !file.IsDir() && manifestExtensions[filepath.Ext(file.Name())] {
+				paths = append(paths, filepath.Join(filename, file.Name()))
+			}
+		}
+		sort.Strings(paths)
+	}
+
+	objects := []runtime.Object{}
+	invalid := []error{}
+	for _, path := range paths {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		decoded, errors := decodeManifests(contents, workers)
+		objects = append(objects, decoded...)
+		for _, err := range errors {
+			invalid = append(invalid, fmt.Errorf("%s: %s", path, err))
+		}
+	}
+	return objects, invalid, nil
+}
+
+func selectObjects(objects []runtime.Object, selector string) ([]runtime.Object, error) {
+	if selector == "" {
+		return objects, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	selected := []runtime.Object{}
+	for _, o := range objects {
+		accessor, err := meta.Accessor(o)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.Matches(labels.Set(accessor.GetLabels())) {
+			selected = append(selected, o)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,126 @@
+package main_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`
+
+var _ = Describe("kubectl plugin", func() {
+	var (
+		dir     string
+		plugin  string
+		args    []string
+		session *gexec.Session
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "plugin")
+		Expect(err).NotTo(HaveOccurred())
+		plugin = path.Join(dir, "kubectl-haornot")
+		Expect(os.Symlink(pathToCLI, plugin)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		session, err = gexec.Start(exec.Command(plugin, args...), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when good file is passed", func() {
+		BeforeEach(func() {
+			args = []string{"-f", path.Join(cwd, "fixtures", "nginx.yml")}
+		})
+
+		It("exits with 0 status code", func() {
+			Eventually(session).Should(gexec.Exit(0))
+		})
+	})
+
+	Context("when selector does not match workloads", func() {
+		BeforeEach(func() {
+			args = []string{"-f", path.Join(cwd, "fixtures", "nginx.yml"), "-l", "name=nginx"}
+		})
+
+		It("analyzes only selected objects", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("only deployments, statefulsets, daemonsets, jobs, cronjobs, pods and replicasets can be analyzed"))
+		})
+	})
+
+	Context("when live object is passed", func() {
+		var (
+			server   *httptest.Server
+			requests []string
+		)
+
+		BeforeEach(func() {
+			manifest, err := ioutil.ReadFile(path.Join(cwd, "fixtures", "bad_nginx.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			deployment, err := yaml.YAMLToJSON(manifest)
+			Expect(err).NotTo(HaveOccurred())
+
+			requests = []string{}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(deployment)
+			}))
+			config := path.Join(dir, "config")
+			Expect(ioutil.WriteFile(config, []byte(fmt.Sprintf(kubeconfig, server.URL)), 0644)).To(Succeed())
+			args = []string{"deployment/nginx", "-n", "payments", "--kubeconfig", config}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("gets it from the cluster and analyzes it", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("At least 2 replicas required"))
+			Expect(requests).To(Equal([]string{"/apis/apps/v1/namespaces/payments/deployments/nginx"}))
+		})
+	})
+
+	Context("when nothing is passed", func() {
+		BeforeEach(func() {
+			args = []string{}
+		})
+
+		It("exits with error", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(Equal(0))
+			Expect(session.Out).To(gbytes.Say("Specify files with -f"))
+		})
+	})
+})
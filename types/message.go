@@ -1,6 +1,25 @@
 package types
 
 type Message struct {
-	Errors []string
-	Name   string
+	Errors   []string
+	Warnings []string
+	Kind     string
+	Name     string
+	// Namespace is empty when the resource does not set it
+	Namespace string
+	// Source is the template that produced the resource, if known
+	Source string
+	// Score is the weighted share of passed checks from 0 to 100
+	Score int
+	// Fixes are automatic fixes for some of the errors
+	Fixes []Fix
+}
+
+// Fix resolves a finding by setting the field at Path to Value
+type Fix struct {
+	// Finding is the error that the fix resolves
+	Finding string
+	// Path is the field in the manifest, e.g. spec, replicas
+	Path  []string
+	Value string
 }
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/alex-slynko/haornot/analyzer"
+	"github.com/alex-slynko/haornot/formatter"
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Editors write files in several steps, so changes are collected for a short time before analyzing
+const watchDebounce = 100 * time.Millisecond
+
+var manifestExtensions = map[string]bool{".yml": true, ".yaml": true, ".json": true}
+
+// watchedFile is a decoded file. Only changed files are decoded again
+type watchedFile struct {
+	objects []runtime.Object
+	invalid []error
+}
+
+type watcher struct {
+	options  analyzer.Options
+	minScore int
+	// files are explicitly passed files
+	files map[string]bool
+	// dirs are passed directories and their subdirectories, where all manifests are analyzed
+	dirs    map[string]bool
+	decoded map[string]*watchedFile
+	// passed is the result of the previous analysis, nil before the first one
+	passed *bool
+}
+
+// watch analyzes files and directories on every change until interrupted
+func watch(paths []string, options analyzer.Options, minScore int) error {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+
+	w := &watcher{options: options, minScore: minScore, files: map[string]bool{}, dirs: map[string]bool{}, decoded: map[string]*watchedFile{}}
+	for _, path := range paths {
+		if err := w.add(notifier, filepath.Clean(path)); err != nil {
+			return err
+		}
+	}
+	w.redraw()
+
+	changed := map[string]bool{}
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case event := <-notifier.Events:
+			path := filepath.Clean(event.Name)
+			if event.Op&fsnotify.Create != 0 && w.dirs[filepath.Dir(path)] {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					w.add(notifier, path)
+				}
+			}
+			if w.tracks(path) {
+				changed[path] = true
+				timer.Reset(watchDebounce)
+			}
+		case err := <-notifier.Errors:
+			return err
+		case <-timer.C:
+			for path := range changed {
+				w.load(path)
+			}
+			changed = map[string]bool{}
+			w.redraw()
+		}
+	}
+}
+
+// add watches file or directory with all subdirectories
+func (w *watcher) add(notifier *fsnotify.Watcher, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		w.files[path] = true
+		w.load(path)
+		// Editors often replace files instead of writing them, so the directory is watched
+		return notifier.Add(filepath.Dir(path))
+	}
+
+	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			w.dirs[path] = true
+			return notifier.Add(path)
+		}
+		if w.tracks(path) {
+			w.load(path)
+		}
+		return nil
+	})
+}
+
+func (w *watcher) tracks(path string) bool {
+	return w.files[path] || (w.dirs[filepath.Dir(path)] && manifestExtensions[filepath.Ext(path)])
+}
+
+// load decodes file again. Removed files are not analyzed anymore
+func (w *watcher) load(path string) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		delete(w.decoded, path)
+		return
+	}
+	if err != nil {
+		w.decoded[path] = &watchedFile{invalid: []error{err}}
+		return
+	}
+	objects, invalid := decodeManifests(contents, w.options.Workers)
+	w.decoded[path] = &watchedFile{objects: objects, invalid: invalid}
+}
+
+// redraw analyzes all files again together, so services are matched with workloads from other files. Only decoding is cached
+func (w *watcher) redraw() {
+	paths := []string{}
+	for path := range w.decoded {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	objects := []runtime.Object{}
+	invalid := []error{}
+	for _, path := range paths {
+		objects = append(objects, w.decoded[path].objects...)
+		for _, err := range w.decoded[path].invalid {
+			invalid = append(invalid, fmt.Errorf("%s: %s", path, err))
+		}
+	}
+
+	// Clear the terminal
+	fmt.Print("\033[H\033[2J")
+	passed, message := false, ""
+	result, err := analyzer.AnalyzeObjects(objects, w.options)
+	if err != nil {
+		message = err.Error()
+	} else {
+		passed, message = check(formatter.ImageFormatter{HideImages: true}, result, invalid, w.minScore, true)
+	}
+	if message == "" {
+		message = "Some checks fail"
+	}
+
+	// Images are shown only when the result changes
+	out := formatter.ImageFormatter{HideImages: w.passed != nil && *w.passed == passed}
+	w.passed = &passed
+	if passed {
+		success(out, message)
+	} else {
+		out.CriticalFail(message)
+	}
+	fmt.Printf("\nWatching %d files. Press Ctrl+C to stop\n", len(paths))
+}